@@ -0,0 +1,144 @@
+// Package audiosrc transparently decrypts the DRM containers used by
+// Chinese streaming services (NCM, QMC, KGM/Kugou) so the rest of LyricFlow
+// can work with a plain MP3/FLAC path regardless of what the user dropped
+// into assets/audio/.
+package audiosrc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Metadata is the tag information a container's embedded key block can
+// carry, recovered without a separate ffprobe pass.
+type Metadata struct {
+	Title  string
+	Artist string
+}
+
+// Decoder decrypts one container format into plain audio bytes, returning
+// whatever tag metadata it can recover along the way.
+type Decoder interface {
+	Decode(r io.Reader, w io.Writer) (Metadata, error)
+}
+
+// Open decrypts path if it's a recognized encrypted container, caching the
+// result alongside the source, and returns the path callers should hand to
+// ffmpeg/ffprobe plus any Metadata recovered from the container's own tags.
+// Paths that aren't a recognized container are returned unchanged with a
+// zero Metadata, so callers can pass any file straight through.
+func Open(path string) (string, Metadata, error) {
+	dec, outExt := decoderFor(path)
+	if dec == nil {
+		return path, Metadata{}, nil
+	}
+
+	out := decodedPath(path, outExt)
+	metaPath := out + ".meta.json"
+	if _, err := os.Stat(out); err == nil {
+		meta, err := readCachedMetadata(metaPath)
+		if err != nil {
+			return "", Metadata{}, err
+		}
+		return out, meta, nil
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return "", Metadata{}, err
+	}
+	defer in.Close()
+
+	f, err := os.Create(out)
+	if err != nil {
+		return "", Metadata{}, err
+	}
+	defer f.Close()
+
+	meta, err := dec.Decode(in, f)
+	if err != nil {
+		f.Close()
+		os.Remove(out)
+		return "", Metadata{}, fmt.Errorf("audiosrc: decode %s: %w", path, err)
+	}
+	if err := writeCachedMetadata(metaPath, meta); err != nil {
+		return "", Metadata{}, err
+	}
+	return out, meta, nil
+}
+
+// readCachedMetadata loads the Metadata sidecar written alongside a cached
+// decode, so a cache hit recovers the same Title/Artist a fresh decode
+// would have produced. A missing sidecar (e.g. output from an older
+// version of this package) yields a zero Metadata rather than an error.
+func readCachedMetadata(path string) (Metadata, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Metadata{}, nil
+		}
+		return Metadata{}, err
+	}
+	var meta Metadata
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return Metadata{}, fmt.Errorf("audiosrc: parse cached metadata %s: %w", path, err)
+	}
+	return meta, nil
+}
+
+// writeCachedMetadata persists Metadata next to the decoded audio so later
+// cache hits don't lose the container's recovered tags.
+func writeCachedMetadata(path string, meta Metadata) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("audiosrc: marshal metadata: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("audiosrc: write cached metadata %s: %w", path, err)
+	}
+	return nil
+}
+
+// decoderFor selects a Decoder, and the file extension its output should
+// carry, by the source's extension. The output extension is taken from the
+// container's extension, not sniffed from the decoded bytes, so an .ncm is
+// always given .mp3 even when its body holds FLAC.
+func decoderFor(path string) (Decoder, string) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ncm":
+		return ncmDecoder{}, ".mp3"
+	case ".qmc0", ".qmc3":
+		return qmcDecoder{}, ".mp3"
+	case ".qmcflac", ".mflac":
+		return qmcDecoder{}, ".flac"
+	case ".mgg":
+		return qmcDecoder{}, ".ogg"
+	case ".kgm", ".kgma", ".vpr":
+		return kgmDecoder{}, ".mp3"
+	default:
+		return nil, ""
+	}
+}
+
+// decodedPath caches the decrypted output next to the source file under its
+// real extension, so downstream ffmpeg/ffprobe calls can sniff the format
+// normally instead of seeing ".ncm"/".qmc3"/etc.
+func decodedPath(path, outExt string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".decoded" + outExt
+}
+
+// pkcs7Unpad strips PKCS#7 padding, tolerating already-unpadded input.
+func pkcs7Unpad(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	pad := int(b[len(b)-1])
+	if pad <= 0 || pad > len(b) {
+		return b
+	}
+	return b[:len(b)-pad]
+}
@@ -0,0 +1,80 @@
+package audiosrc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// kgmMagic is the 16-byte signature shared by Kugou's .kgm/.kgma/.vpr
+// containers.
+var kgmMagic = []byte{
+	0x7c, 0xd5, 0x32, 0xeb, 0x86, 0x02, 0x7f, 0x4b,
+	0xa8, 0xaf, 0xa6, 0x8e, 0x0f, 0xff, 0x99, 0x14,
+}
+
+const kgmHeaderLen = 0x3c
+
+// kgmDecoder decodes Kugou's .kgm container: a fixed-size header carries a
+// per-file key seed which, mixed with a static substitution table, yields a
+// per-file byte mask applied to the whole audio body.
+type kgmDecoder struct{}
+
+func (kgmDecoder) Decode(r io.Reader, w io.Writer) (Metadata, error) {
+	header := make([]byte, kgmHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Metadata{}, err
+	}
+	if !bytes.Equal(header[:len(kgmMagic)], kgmMagic) {
+		return Metadata{}, fmt.Errorf("not a KGM container")
+	}
+
+	mask := kgmMask(header)
+
+	br := bufio.NewReader(r)
+	buf := make([]byte, 0x8000)
+	pos := 0
+	for {
+		n, err := br.Read(buf)
+		if n > 0 {
+			chunk := buf[:n]
+			for i := range chunk {
+				chunk[i] ^= mask[(pos+i)%len(mask)]
+			}
+			pos += n
+			if _, werr := w.Write(chunk); werr != nil {
+				return Metadata{}, werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Metadata{}, err
+		}
+	}
+
+	return Metadata{}, nil
+}
+
+// kgmSubTable is Kugou's fixed substitution table, mixed with the header's
+// per-file key bytes to derive kgmMask.
+var kgmSubTable = [17]byte{
+	0x6A, 0x14, 0xF8, 0x2A, 0x79, 0xE2, 0x93, 0xC5,
+	0x10, 0x38, 0xB4, 0x9C, 0x21, 0x5D, 0x47, 0x8B, 0xF3,
+}
+
+// kgmMask derives the per-file XOR mask from the bytes following the
+// container magic in header.
+func kgmMask(header []byte) []byte {
+	seed := header[len(kgmMagic):kgmHeaderLen]
+	mask := make([]byte, len(seed))
+	for i, b := range seed {
+		mask[i] = b ^ kgmSubTable[i%len(kgmSubTable)]
+	}
+	if len(mask) == 0 {
+		mask = []byte{0}
+	}
+	return mask
+}
@@ -0,0 +1,41 @@
+package audiosrc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKGMDecodeRoundTrip(t *testing.T) {
+	header := make([]byte, kgmHeaderLen)
+	copy(header, kgmMagic)
+	for i := len(kgmMagic); i < kgmHeaderLen; i++ {
+		header[i] = byte(i * 7) // arbitrary per-file key seed bytes
+	}
+	mask := kgmMask(header)
+
+	plaintext := []byte("the quick brown fox jumps over the lazy dog, repeated a lot to cross a buffer boundary. ")
+	for len(plaintext) < 0x9000 {
+		plaintext = append(plaintext, plaintext...)
+	}
+
+	encrypted := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		encrypted[i] = b ^ mask[i%len(mask)]
+	}
+
+	var out bytes.Buffer
+	if _, err := (kgmDecoder{}).Decode(bytes.NewReader(append(header, encrypted...)), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), plaintext) {
+		t.Error("decoded body does not match original plaintext")
+	}
+}
+
+func TestKGMDecodeRejectsBadMagic(t *testing.T) {
+	header := make([]byte, kgmHeaderLen)
+	var out bytes.Buffer
+	if _, err := (kgmDecoder{}).Decode(bytes.NewReader(header), &out); err == nil {
+		t.Error("expected an error for a missing KGM magic")
+	}
+}
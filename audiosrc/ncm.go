@@ -0,0 +1,181 @@
+package audiosrc
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ncmMagic is the 8-byte signature every .ncm file starts with.
+var ncmMagic = []byte{0x43, 0x54, 0x45, 0x4e, 0x46, 0x44, 0x41, 0x4d}
+
+// ncmCoreKey and ncmMetaKey are the fixed AES-128-ECB keys NetEase bakes
+// into every .ncm file to wrap the per-file RC4 key and the embedded 163key
+// metadata JSON; they're identical across all files, recovered by the
+// community's ncmdump reverse-engineering effort.
+var (
+	ncmCoreKey = []byte{0x68, 0x7a, 0x48, 0x52, 0x41, 0x6d, 0x73, 0x6f, 0x35, 0x6b, 0x6d, 0x49, 0x79, 0x31, 0x32, 0x30}
+	ncmMetaKey = []byte{0x23, 0x31, 0x34, 0x6c, 0x6a, 0x6b, 0x5f, 0x21, 0x5c, 0x5d, 0x26, 0x30, 0x75, 0x3c, 0x27, 0x28}
+)
+
+const ncmMetaPrefix = "163 key(Don't modify):"
+
+// ncmDecoder decodes NetEase Cloud Music's .ncm container: an AES-wrapped
+// RC4 key, an AES-wrapped metadata block, a cover image, then the RC4-KSA
+// keystream-obfuscated audio body.
+type ncmDecoder struct{}
+
+func (ncmDecoder) Decode(r io.Reader, w io.Writer) (Metadata, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, 8)
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return Metadata{}, err
+	}
+	if !bytes.Equal(magic, ncmMagic) {
+		return Metadata{}, fmt.Errorf("not an NCM container")
+	}
+	if _, err := io.CopyN(io.Discard, br, 2); err != nil { // unused gap
+		return Metadata{}, err
+	}
+
+	keyBlock, err := readNCMAESBlock(br, ncmCoreKey, 0x64)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("key block: %w", err)
+	}
+	if len(keyBlock) <= 17 {
+		return Metadata{}, fmt.Errorf("key block too short")
+	}
+	keyBox := ncmKeyBox(keyBlock[17:]) // first 17 bytes are a fixed "neteasecloudmusic" header
+
+	metaBlock, err := readNCMAESBlock(br, ncmMetaKey, 0x63)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("meta block: %w", err)
+	}
+	meta := parseNCMMeta(metaBlock)
+
+	if _, err := io.CopyN(io.Discard, br, 9); err != nil { // crc32 + gap
+		return Metadata{}, err
+	}
+	var coverLen uint32
+	if err := binary.Read(br, binary.LittleEndian, &coverLen); err != nil {
+		return Metadata{}, err
+	}
+	if _, err := io.CopyN(io.Discard, br, int64(coverLen)); err != nil {
+		return Metadata{}, err
+	}
+
+	buf := make([]byte, 0x8000)
+	pos := 0
+	for {
+		n, err := br.Read(buf)
+		if n > 0 {
+			chunk := buf[:n]
+			for i := range chunk {
+				chunk[i] ^= keyBox[(pos+i)&0xff]
+			}
+			pos += n
+			if _, werr := w.Write(chunk); werr != nil {
+				return Metadata{}, werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Metadata{}, err
+		}
+	}
+
+	return meta, nil
+}
+
+// readNCMAESBlock reads a "length-prefixed, xor-masked, AES-128-ECB
+// encrypted" block as used for both the key and metadata sections of an
+// .ncm header.
+func readNCMAESBlock(r io.Reader, key []byte, xorMask byte) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		return nil, nil
+	}
+
+	raw := make([]byte, length)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, err
+	}
+	for i := range raw {
+		raw[i] ^= xorMask
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("block not AES-aligned")
+	}
+	out := make([]byte, len(raw))
+	for i := 0; i < len(raw); i += aes.BlockSize {
+		block.Decrypt(out[i:i+aes.BlockSize], raw[i:i+aes.BlockSize])
+	}
+	return pkcs7Unpad(out), nil
+}
+
+// ncmKeyBox runs the RC4 key-scheduling algorithm over key and precomputes
+// a 256-byte lookup table so the audio body can be decoded with a single
+// indexed XOR per byte instead of a full RC4 PRGA stream.
+func ncmKeyBox(key []byte) [256]byte {
+	var s [256]byte
+	for i := range s {
+		s[i] = byte(i)
+	}
+	j := 0
+	for i := 0; i < 256; i++ {
+		j = (j + int(s[i]) + int(key[i%len(key)])) & 0xff
+		s[i], s[j] = s[j], s[i]
+	}
+
+	var box [256]byte
+	for i := 0; i < 256; i++ {
+		si := (i + 1) & 0xff
+		box[i] = s[(int(s[si])+int(s[(int(s[si])+si)&0xff]))&0xff]
+	}
+	return box
+}
+
+// parseNCMMeta decodes the embedded "163 key" JSON block into Metadata.
+func parseNCMMeta(block []byte) Metadata {
+	if block == nil {
+		return Metadata{}
+	}
+	s := strings.TrimPrefix(string(block), ncmMetaPrefix)
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return Metadata{}
+	}
+
+	var doc struct {
+		MusicName string          `json:"musicName"`
+		Artist    [][]interface{} `json:"artist"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return Metadata{}
+	}
+
+	var artist string
+	if len(doc.Artist) > 0 && len(doc.Artist[0]) > 0 {
+		if name, ok := doc.Artist[0][0].(string); ok {
+			artist = name
+		}
+	}
+	return Metadata{Title: doc.MusicName, Artist: artist}
+}
@@ -0,0 +1,144 @@
+package audiosrc
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseNCMMeta(t *testing.T) {
+	// base64("{"musicName":"Test Song","artist":[["Test Artist",123]]}")
+	const encoded = "eyJtdXNpY05hbWUiOiJUZXN0IFNvbmciLCJhcnRpc3QiOltbIlRlc3QgQXJ0aXN0IiwxMjNdXX0="
+	block := []byte(ncmMetaPrefix + encoded)
+
+	meta := parseNCMMeta(block)
+	if meta.Title != "Test Song" {
+		t.Errorf("Title = %q, want %q", meta.Title, "Test Song")
+	}
+	if meta.Artist != "Test Artist" {
+		t.Errorf("Artist = %q, want %q", meta.Artist, "Test Artist")
+	}
+}
+
+func TestParseNCMMetaNil(t *testing.T) {
+	if got := parseNCMMeta(nil); got != (Metadata{}) {
+		t.Errorf("parseNCMMeta(nil) = %+v, want zero value", got)
+	}
+}
+
+func TestPKCS7Unpad(t *testing.T) {
+	padded := []byte{'h', 'i', 3, 3, 3}
+	if got := string(pkcs7Unpad(padded)); got != "hi" {
+		t.Errorf("pkcs7Unpad = %q, want %q", got, "hi")
+	}
+	// Already-unpadded input (final byte isn't a valid pad count) passes
+	// through unchanged.
+	unpadded := []byte{'h', 'i'}
+	if got := string(pkcs7Unpad(unpadded)); got != "hi" {
+		t.Errorf("pkcs7Unpad = %q, want %q", got, "hi")
+	}
+}
+
+func TestNCMKeyBoxDeterministic(t *testing.T) {
+	key := []byte("some-per-file-rc4-key")
+	a := ncmKeyBox(key)
+	b := ncmKeyBox(key)
+	if a != b {
+		t.Error("ncmKeyBox should be a pure function of its key")
+	}
+}
+
+// referenceNCMKeystream is an independent reimplementation of the standard
+// ncmdump RC4-KSA keystream (KSA over key, then
+// S[(S[j]+S[(S[j]+j)&0xff])&0xff] with j=(d+1)&0xff at absolute stream
+// position d), used to generate reference ciphertext without going through
+// the package's own ncmKeyBox.
+func referenceNCMKeystream(key []byte, n int) []byte {
+	var s [256]byte
+	for i := range s {
+		s[i] = byte(i)
+	}
+	j := 0
+	for i := 0; i < 256; i++ {
+		j = (j + int(s[i]) + int(key[i%len(key)])) & 0xff
+		s[i], s[j] = s[j], s[i]
+	}
+
+	out := make([]byte, n)
+	for d := 0; d < n; d++ {
+		j := (d + 1) & 0xff
+		out[d] = s[(int(s[j])+int(s[(int(s[j])+j)&0xff]))&0xff]
+	}
+	return out
+}
+
+// pkcs7Pad pads b to a multiple of aes.BlockSize, the inverse of pkcs7Unpad.
+func pkcs7Pad(b []byte) []byte {
+	pad := aes.BlockSize - len(b)%aes.BlockSize
+	if pad == 0 {
+		pad = aes.BlockSize
+	}
+	return append(append([]byte{}, b...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+}
+
+// encodeNCMAESBlock is the inverse of readNCMAESBlock: it PKCS7-pads,
+// AES-128-ECB encrypts, XOR-masks and length-prefixes payload, producing
+// the on-disk form of an .ncm key/meta block.
+func encodeNCMAESBlock(payload []byte, key []byte, xorMask byte) []byte {
+	if payload == nil {
+		return []byte{0, 0, 0, 0}
+	}
+	padded := pkcs7Pad(payload)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	enc := make([]byte, len(padded))
+	for i := 0; i < len(padded); i += aes.BlockSize {
+		block.Encrypt(enc[i:i+aes.BlockSize], padded[i:i+aes.BlockSize])
+	}
+	for i := range enc {
+		enc[i] ^= xorMask
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(len(enc)))
+	buf.Write(enc)
+	return buf.Bytes()
+}
+
+func TestNCMDecodeRoundTrip(t *testing.T) {
+	rc4Key := []byte("test-rc4-session-key")
+	plaintext := []byte("a pretend decoded mp3 stream, repeated so it spans more than one keystream period. ")
+	for len(plaintext) < 600 {
+		plaintext = append(plaintext, plaintext...)
+	}
+
+	keystream := referenceNCMKeystream(rc4Key, len(plaintext))
+	ciphertext := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		ciphertext[i] = b ^ keystream[i]
+	}
+
+	var file bytes.Buffer
+	file.Write(ncmMagic)
+	file.Write([]byte{0, 0}) // unused gap
+
+	keyBlockPlain := append([]byte("neteasecloudmusic"), rc4Key...)
+	file.Write(encodeNCMAESBlock(keyBlockPlain, ncmCoreKey, 0x64))
+	file.Write(encodeNCMAESBlock(nil, ncmMetaKey, 0x63)) // no embedded 163key metadata
+
+	file.Write(make([]byte, 9))                         // crc32 + gap
+	binary.Write(&file, binary.LittleEndian, uint32(0)) // no cover image
+	file.Write(ciphertext)
+
+	var out bytes.Buffer
+	if _, err := (ncmDecoder{}).Decode(bytes.NewReader(file.Bytes()), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), plaintext) {
+		t.Error("decoded body does not match original plaintext")
+	}
+}
@@ -0,0 +1,85 @@
+package audiosrc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+)
+
+// qmcStaticMask is the fixed 128-byte XOR table used by the original
+// qmc0/qmc3 container variant, which has no per-file key at all.
+var qmcStaticMask = [128]byte{
+	0xc3, 0x4a, 0xd6, 0xca, 0x90, 0x67, 0xf7, 0x72, 0x48, 0x4f, 0x00, 0xf1, 0xd9, 0x6d, 0x5d, 0x9d,
+	0x2e, 0x71, 0x41, 0x06, 0x4d, 0x9c, 0xf5, 0x9f, 0xf4, 0x3c, 0xe3, 0xe9, 0x4e, 0x2c, 0x1f, 0x5d,
+	0xef, 0xaf, 0x27, 0x37, 0x2d, 0x0e, 0x61, 0xac, 0x3e, 0xcf, 0x86, 0xa3, 0xdb, 0x58, 0x06, 0x6a,
+	0x94, 0x4a, 0x81, 0xaf, 0xc5, 0xbe, 0x4a, 0x7d, 0x30, 0x30, 0xf3, 0xec, 0x32, 0x85, 0xb8, 0x67,
+	0xcb, 0xf0, 0x2a, 0x3e, 0x63, 0xf0, 0xe4, 0x2e, 0x8c, 0x31, 0x1a, 0xbd, 0x9b, 0x56, 0xbc, 0x13,
+	0x4a, 0x7e, 0xee, 0x03, 0x86, 0x6d, 0x1a, 0xef, 0xb3, 0xc6, 0x45, 0x2c, 0x86, 0x09, 0xd9, 0xf8,
+	0x5c, 0xa9, 0x22, 0x0e, 0x8e, 0xbf, 0xa9, 0x54, 0x2e, 0xa8, 0xb7, 0xb7, 0x4d, 0x1e, 0xa8, 0x47,
+	0x82, 0x43, 0x15, 0xcb, 0x74, 0x69, 0x4b, 0x1f, 0xaf, 0xb0, 0x73, 0xfc, 0xd5, 0x2e, 0x1c, 0xba,
+}
+
+// qmcEOFTrailers marks the footer a QMC file carries when it embeds its own
+// per-file "ekey" (the newer map-cipher variant) instead of relying on
+// qmcStaticMask.
+var qmcEOFTrailers = [][]byte{[]byte("STag"), []byte("QTag")}
+
+// qmcDecoder decodes Tencent QQ Music's QMC container. It supports the
+// classic fixed-mask variant used by most qmc0/qmc3 rips; files carrying an
+// embedded per-file ekey footer are detected but decoded with the same
+// static mask, which is correct only for the subset of the map-cipher that
+// degrades to it — full map-cipher support needs the ekey RC4 derivation
+// this package doesn't implement yet.
+type qmcDecoder struct{}
+
+func (qmcDecoder) Decode(r io.Reader, w io.Writer) (Metadata, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	body := data
+	if key := qmcEmbeddedKey(data); key != nil {
+		body = data[:len(data)-qmcFooterLen(data)]
+	}
+
+	buf := bufio.NewWriter(w)
+	for i, b := range body {
+		buf.WriteByte(b ^ qmcStaticMask[i%len(qmcStaticMask)])
+	}
+	if err := buf.Flush(); err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{}, nil
+}
+
+// qmcEmbeddedKey reports the base64-encoded per-file ekey trailing the file,
+// if any, by checking for one of the known footer tags.
+func qmcEmbeddedKey(data []byte) []byte {
+	for _, tag := range qmcEOFTrailers {
+		if len(data) > len(tag) && bytes.HasSuffix(data, tag) {
+			keyLen := qmcFooterLen(data)
+			if keyLen <= 0 || keyLen > len(data) {
+				return nil
+			}
+			raw := data[len(data)-keyLen : len(data)-len(tag)-4]
+			key, err := base64.StdEncoding.DecodeString(string(raw))
+			if err != nil {
+				return nil
+			}
+			return key
+		}
+	}
+	return nil
+}
+
+// qmcFooterLen returns the size of the trailing ekey block (4-byte little
+// endian length, plus the tag itself, plus the base64 key bytes).
+func qmcFooterLen(data []byte) int {
+	if len(data) < 8 {
+		return 0
+	}
+	n := int(data[len(data)-8]) | int(data[len(data)-7])<<8 | int(data[len(data)-6])<<16 | int(data[len(data)-5])<<24
+	return n + 8
+}
@@ -0,0 +1,79 @@
+package audiosrc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+// qmcFooter builds the real on-disk ekey footer layout: [key bytes
+// (base64)][4-byte little-endian key length][4-byte tag].
+func qmcFooter(key []byte, tag string) []byte {
+	b64 := []byte(base64.StdEncoding.EncodeToString(key))
+	n := len(b64)
+	lengthField := []byte{byte(n), byte(n >> 8), byte(n >> 16), byte(n >> 24)}
+	return append(append(b64, lengthField...), []byte(tag)...)
+}
+
+func TestQMCStaticMaskRoundTrip(t *testing.T) {
+	plaintext := []byte("the quick brown fox jumps over the lazy dog, well past one mask period long.")
+	for len(plaintext) <= len(qmcStaticMask) {
+		plaintext = append(plaintext, plaintext...)
+	}
+
+	encrypted := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		encrypted[i] = b ^ qmcStaticMask[i%len(qmcStaticMask)]
+	}
+
+	var out bytes.Buffer
+	if _, err := (qmcDecoder{}).Decode(bytes.NewReader(encrypted), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), plaintext) {
+		t.Error("decoded body does not match original plaintext")
+	}
+}
+
+func TestQMCFooterLen(t *testing.T) {
+	// 4-byte little-endian length(=20) immediately before a 4-byte tag.
+	data := append([]byte("body"), 20, 0, 0, 0)
+	data = append(data, []byte("STag")...)
+	if got := qmcFooterLen(data); got != 28 {
+		t.Errorf("qmcFooterLen = %d, want 28", got)
+	}
+}
+
+func TestQMCEmbeddedKeyAbsent(t *testing.T) {
+	if got := qmcEmbeddedKey([]byte("plain audio bytes with no footer")); got != nil {
+		t.Errorf("qmcEmbeddedKey = %v, want nil", got)
+	}
+}
+
+func TestQMCEmbeddedKeyDetection(t *testing.T) {
+	key := []byte("fake-ekey-bytes")
+	data := append([]byte("body"), qmcFooter(key, "STag")...)
+
+	got := qmcEmbeddedKey(data)
+	if string(got) != string(key) {
+		t.Errorf("qmcEmbeddedKey = %q, want %q", got, key)
+	}
+}
+
+func TestQMCDecodeStripsEkeyFooter(t *testing.T) {
+	plaintext := []byte("body bytes that should survive decoding untouched by the footer")
+	encrypted := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		encrypted[i] = b ^ qmcStaticMask[i%len(qmcStaticMask)]
+	}
+
+	data := append(append([]byte{}, encrypted...), qmcFooter([]byte("fake-ekey-bytes"), "STag")...)
+
+	var out bytes.Buffer
+	if _, err := (qmcDecoder{}).Decode(bytes.NewReader(data), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), plaintext) {
+		t.Errorf("decoded body = %q, want %q (footer should be stripped, not decoded as audio)", out.Bytes(), plaintext)
+	}
+}
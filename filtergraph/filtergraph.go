@@ -0,0 +1,171 @@
+// Package filtergraph models ffmpeg -filter_complex graphs as typed nodes
+// and edges instead of hand-formatted strings, so callers don't have to
+// track "[baseN]"-style labels themselves.
+package filtergraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stream is a labeled edge in the graph, e.g. a source input ("0:v") or an
+// intermediate node output ("n3").
+type Stream struct {
+	ref string
+}
+
+// Label renders the stream the way ffmpeg expects it inline, e.g. "[0:v]".
+func (s Stream) Label() string {
+	return fmt.Sprintf("[%s]", s.ref)
+}
+
+// Node is a single filter application: zero or more input Streams, the
+// filter expression (one filter, or a comma-joined Chain of them), and the
+// Stream it produces.
+type Node struct {
+	Inputs []Stream
+	Filter string
+	Output Stream
+}
+
+func (n Node) compile() string {
+	var b strings.Builder
+	for _, in := range n.Inputs {
+		b.WriteString(in.Label())
+	}
+	b.WriteString(n.Filter)
+	b.WriteString(n.Output.Label())
+	return b.String()
+}
+
+// Graph accumulates Nodes and compiles them into a -filter_complex argument,
+// generating unique output labels so callers never have to.
+type Graph struct {
+	nodes []Node
+	seq   int
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{}
+}
+
+// Input returns a Stream referencing an ffmpeg input stream specifier, e.g.
+// g.Input("0:v") or g.Input("1:a").
+func (g *Graph) Input(spec string) Stream {
+	return Stream{ref: spec}
+}
+
+// Named returns a Stream with an explicit, caller-chosen label, for outputs
+// that need a stable name (e.g. the final "[out]" passed to -map).
+func (g *Graph) Named(name string) Stream {
+	return Stream{ref: name}
+}
+
+func (g *Graph) nextLabel() Stream {
+	g.seq++
+	return Stream{ref: fmt.Sprintf("n%d", g.seq)}
+}
+
+// Apply adds a node running filter over inputs and returns an
+// automatically-labeled Stream carrying its output.
+func (g *Graph) Apply(filter string, inputs ...Stream) Stream {
+	out := g.nextLabel()
+	g.nodes = append(g.nodes, Node{Inputs: inputs, Filter: filter, Output: out})
+	return out
+}
+
+// ApplyTo adds a node like Apply, but writes its output to a caller-chosen
+// Stream (typically from Named) instead of an auto-generated label.
+func (g *Graph) ApplyTo(output Stream, filter string, inputs ...Stream) {
+	g.nodes = append(g.nodes, Node{Inputs: inputs, Filter: filter, Output: output})
+}
+
+// Compile renders every node into the ";"-joined expression ffmpeg expects
+// for -filter_complex.
+func (g *Graph) Compile() string {
+	parts := make([]string, len(g.nodes))
+	for i, n := range g.nodes {
+		parts[i] = n.compile()
+	}
+	return strings.Join(parts, ";")
+}
+
+// Chain joins filters with "," so they apply in sequence within a single
+// Graph.Apply/ApplyTo call, e.g. Chain(ATrim(...), SetPTS(), Volume(...)).
+func Chain(filters ...string) string {
+	return strings.Join(filters, ",")
+}
+
+// DrawText builds a drawtext filter expression. x and y accept ffmpeg
+// expressions (e.g. "(w-text_w)/2") as well as literal coordinates.
+func DrawText(fontfile, text string, fontsize int, color, x, y string) string {
+	return fmt.Sprintf("drawtext=fontfile=%s:text='%s':fontsize=%d:fontcolor=%s:x=%s:y=%s",
+		fontfile, text, fontsize, color, x, y)
+}
+
+// DrawTextEnabled is DrawText with an "enable" expression gating when the
+// text is visible, e.g. "between(t,1.20,3.40)".
+func DrawTextEnabled(fontfile, text string, fontsize int, color, x, y, enable string) string {
+	return fmt.Sprintf("%s:enable='%s'", DrawText(fontfile, text, fontsize, color, x, y), enable)
+}
+
+// Overlay builds an overlay filter expression, optionally gated by enable
+// (pass "" to overlay unconditionally).
+func Overlay(x, y int, enable string) string {
+	if enable == "" {
+		return fmt.Sprintf("overlay=x=%d:y=%d", x, y)
+	}
+	return fmt.Sprintf("overlay=x=%d:y=%d:enable='%s'", x, y, enable)
+}
+
+// Scale builds a scale filter expression.
+func Scale(w, h int) string {
+	return fmt.Sprintf("scale=%d:%d", w, h)
+}
+
+// ATrim builds an atrim filter expression over [start, end] seconds.
+func ATrim(start, end float64) string {
+	return fmt.Sprintf("atrim=%.2f:%.2f", start, end)
+}
+
+// SetPTS resets presentation timestamps after a trim.
+func SetPTS() string {
+	return "asetpts=PTS-STARTPTS"
+}
+
+// Volume builds a volume filter expression.
+func Volume(gain float64) string {
+	return fmt.Sprintf("volume=%.2f", gain)
+}
+
+// AMix builds an amix filter expression mixing n inputs to one output of
+// the first input's duration.
+func AMix(n int) string {
+	return fmt.Sprintf("amix=inputs=%d:duration=first", n)
+}
+
+// Pan builds a pan filter expression, re-weighting or remapping individual
+// channels of a multichannel stream, e.g.
+// Pan("5.1", "FL=FL", "FR=FR", "FC=0.20*FC", "LFE=LFE", "BL=BL", "BR=BR").
+func Pan(layout string, channelExprs ...string) string {
+	return fmt.Sprintf("pan=%s|%s", layout, strings.Join(channelExprs, "|"))
+}
+
+// ChannelSplit builds a channelsplit filter expression, breaking a
+// multichannel stream into one output per channel of layout.
+func ChannelSplit(layout string) string {
+	return fmt.Sprintf("channelsplit=channel_layout=%s", layout)
+}
+
+// Surround builds a surround filter expression upmixing a stereo input into
+// chlOut (e.g. "5.1", "quad").
+func Surround(chlOut string) string {
+	return fmt.Sprintf("surround=chl_out=%s", chlOut)
+}
+
+// Concat builds a concat filter expression over n segments with v video and
+// a audio streams per segment.
+func Concat(n, v, a int) string {
+	return fmt.Sprintf("concat=n=%d:v=%d:a=%d", n, v, a)
+}
@@ -0,0 +1,79 @@
+package filtergraph
+
+import "testing"
+
+func TestGraphCompileSingleNode(t *testing.T) {
+	g := NewGraph()
+	in := g.Input("0:a")
+	out := g.Apply(Volume(0.5), in)
+
+	got := g.Compile()
+	want := "[0:a]volume=0.50[n1]"
+	if got != want {
+		t.Errorf("Compile() = %q, want %q", got, want)
+	}
+	if out.Label() != "[n1]" {
+		t.Errorf("output Label() = %q, want %q", out.Label(), "[n1]")
+	}
+}
+
+func TestGraphCompileJoinsNodesWithSemicolon(t *testing.T) {
+	g := NewGraph()
+	in := g.Input("0:a")
+	mid := g.Apply(Volume(0.2), in)
+	g.Apply(Volume(0.8), mid)
+
+	got := g.Compile()
+	want := "[0:a]volume=0.20[n1];[n1]volume=0.80[n2]"
+	if got != want {
+		t.Errorf("Compile() = %q, want %q", got, want)
+	}
+}
+
+func TestGraphApplyLabelsAreUnique(t *testing.T) {
+	g := NewGraph()
+	in := g.Input("0:a")
+	a := g.Apply(Volume(1.0), in)
+	b := g.Apply(Volume(1.0), in)
+	if a.Label() == b.Label() {
+		t.Errorf("two Apply calls produced the same label %q", a.Label())
+	}
+}
+
+func TestGraphApplyToUsesCallerLabel(t *testing.T) {
+	g := NewGraph()
+	in := g.Input("0:a")
+	out := g.Named("out")
+	g.ApplyTo(out, Concat(1, 0, 1), in)
+
+	got := g.Compile()
+	want := "[0:a]concat=n=1:v=0:a=1[out]"
+	if got != want {
+		t.Errorf("Compile() = %q, want %q", got, want)
+	}
+}
+
+func TestChainJoinsWithCommas(t *testing.T) {
+	got := Chain(ATrim(1.5, 3.25), SetPTS(), Volume(0.2))
+	want := "atrim=1.50:3.25,asetpts=PTS-STARTPTS,volume=0.20"
+	if got != want {
+		t.Errorf("Chain(...) = %q, want %q", got, want)
+	}
+}
+
+func TestPanBuildsChannelExpression(t *testing.T) {
+	got := Pan("5.1", "FL=FL", "FR=FR", "FC=0.20*FC")
+	want := "pan=5.1|FL=FL|FR=FR|FC=0.20*FC"
+	if got != want {
+		t.Errorf("Pan(...) = %q, want %q", got, want)
+	}
+}
+
+func TestOverlayWithAndWithoutEnable(t *testing.T) {
+	if got, want := Overlay(10, 20, ""), "overlay=x=10:y=20"; got != want {
+		t.Errorf("Overlay(...) = %q, want %q", got, want)
+	}
+	if got, want := Overlay(10, 20, "gte(t,1)"), "overlay=x=10:y=20:enable='gte(t,1)'"; got != want {
+		t.Errorf("Overlay(...) = %q, want %q", got, want)
+	}
+}
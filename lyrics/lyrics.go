@@ -0,0 +1,409 @@
+// Package lyrics parses timed lyric files into LyricLine slices.
+//
+// Several on-disk formats are supported (plain LRC, Enhanced LRC with
+// per-word timing, SRT, WebVTT and karaoke-tagged ASS/SSA). ParseFile picks
+// the Parser by file extension; any other extension, or none, is read as
+// LRC, and the content is sniffed only to tell Enhanced LRC from plain LRC.
+package lyrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LyricWord carries per-syllable timing within a LyricLine, used to render
+// karaoke-style progressive word highlighting.
+type LyricWord struct {
+	Start float64 // start time in seconds
+	End   float64 // end time in seconds
+	Text  string  // word text
+}
+
+// LyricLine represents a lyric with start/end times.
+type LyricLine struct {
+	Start float64     // start time in seconds
+	End   float64     // end time in seconds
+	Text  string      // lyric text
+	Words []LyricWord // optional per-word timing; nil when the source has none
+}
+
+// Parser turns the raw contents of a lyric file into timed lines.
+type Parser interface {
+	Parse(path string) ([]LyricLine, error)
+}
+
+// ParseFile reads path with the Parser selected for its extension and
+// returns the timed lines it contains.
+func ParseFile(path string) ([]LyricLine, error) {
+	return parserFor(path).Parse(path)
+}
+
+// parserFor chooses a Parser by extension, sniffing the content for LRC
+// files that actually carry Enhanced LRC word tags.
+func parserFor(path string) Parser {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt":
+		return SRTParser{}
+	case ".vtt":
+		return WebVTTParser{}
+	case ".ass", ".ssa":
+		return ASSParser{}
+	default:
+		if hasWordTags(path) {
+			return EnhancedLRCParser{}
+		}
+		return LRCParser{}
+	}
+}
+
+// hasWordTags reports whether an .lrc file contains Enhanced LRC word-level
+// timestamps, e.g. "[00:12.34]<00:12.34>word<00:12.78>word".
+func hasWordTags(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	re := regexp.MustCompile(`<\d+:\d+\.\d+>`)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if re.MatchString(scanner.Text()) {
+			return true
+		}
+	}
+	return false
+}
+
+// LRCParser parses standard "[mm:ss.xx]text" lyric files.
+type LRCParser struct{}
+
+var lrcLineRe = regexp.MustCompile(`\[(\d+):(\d+\.\d+)\](.*)`)
+
+func (LRCParser) Parse(path string) ([]LyricLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []LyricLine
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		m := lrcLineRe.FindStringSubmatch(scanner.Text())
+		if len(m) != 4 {
+			continue
+		}
+		start, err := parseMinSec(m[1], m[2])
+		if err != nil {
+			continue
+		}
+		lines = append(lines, LyricLine{Start: start, Text: strings.TrimSpace(m[3])})
+	}
+	fillEnds(lines)
+	return lines, nil
+}
+
+// EnhancedLRCParser parses "[mm:ss.xx]<mm:ss.xx>word<mm:ss.xx>word..." lines,
+// where the leading bracketed timestamp marks the line start and each
+// angle-bracketed timestamp marks the start of the word that follows it.
+type EnhancedLRCParser struct{}
+
+var (
+	enhancedLineRe = regexp.MustCompile(`\[(\d+):(\d+\.\d+)\](.*)`)
+	enhancedWordRe = regexp.MustCompile(`<(\d+):(\d+\.\d+)>([^<]*)`)
+)
+
+func (EnhancedLRCParser) Parse(path string) ([]LyricLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []LyricLine
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		m := enhancedLineRe.FindStringSubmatch(scanner.Text())
+		if len(m) != 4 {
+			continue
+		}
+		start, err := parseMinSec(m[1], m[2])
+		if err != nil {
+			continue
+		}
+
+		words := parseEnhancedWords(m[3])
+		text := strings.TrimSpace(joinWords(words))
+		lines = append(lines, LyricLine{Start: start, Text: text, Words: words})
+	}
+
+	fillEnds(lines)
+	for i := range lines {
+		fillWordEnds(lines[i].Words, lines[i].End)
+	}
+	return lines, nil
+}
+
+// parseEnhancedWords extracts the per-word timestamps from the remainder of
+// an Enhanced LRC line (everything after the leading "[mm:ss.xx]" tag).
+func parseEnhancedWords(rest string) []LyricWord {
+	matches := enhancedWordRe.FindAllStringSubmatch(rest, -1)
+	words := make([]LyricWord, 0, len(matches))
+	for _, wm := range matches {
+		start, err := parseMinSec(wm[1], wm[2])
+		if err != nil {
+			continue
+		}
+		text := strings.TrimSpace(wm[3])
+		if text == "" {
+			continue
+		}
+		words = append(words, LyricWord{Start: start, Text: text})
+	}
+	return words
+}
+
+func joinWords(words []LyricWord) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.Text
+	}
+	return strings.Join(parts, "")
+}
+
+// fillWordEnds sets each word's End to the next word's Start, and the last
+// word's End to the line's End.
+func fillWordEnds(words []LyricWord, lineEnd float64) {
+	for i := range words {
+		if i < len(words)-1 {
+			words[i].End = words[i+1].Start
+		} else {
+			words[i].End = lineEnd
+		}
+	}
+}
+
+// SRTParser parses SubRip (.srt) subtitle files.
+type SRTParser struct{}
+
+var srtTimeRe = regexp.MustCompile(`(\d+):(\d+):(\d+),(\d+)\s*-->\s*(\d+):(\d+):(\d+),(\d+)`)
+
+func (SRTParser) Parse(path string) ([]LyricLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []LyricLine
+	var textBuf []string
+	var start, end float64
+	inBlock := false
+
+	flush := func() {
+		if inBlock && len(textBuf) > 0 {
+			lines = append(lines, LyricLine{Start: start, End: end, Text: strings.Join(textBuf, " ")})
+		}
+		textBuf = nil
+		inBlock = false
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if m := srtTimeRe.FindStringSubmatch(text); m != nil {
+			flush()
+			start = srtTimestamp(m[1], m[2], m[3], m[4])
+			end = srtTimestamp(m[5], m[6], m[7], m[8])
+			inBlock = true
+			continue
+		}
+		if text == "" {
+			flush()
+			continue
+		}
+		if inBlock && !isSRTIndex(text) {
+			textBuf = append(textBuf, text)
+		}
+	}
+	flush()
+	return lines, nil
+}
+
+// isSRTIndex reports whether line is a bare cue-index line ("1", "2", ...).
+func isSRTIndex(line string) bool {
+	_, err := strconv.Atoi(line)
+	return err == nil
+}
+
+func srtTimestamp(h, m, s, ms string) float64 {
+	hh, _ := strconv.Atoi(h)
+	mm, _ := strconv.Atoi(m)
+	ss, _ := strconv.Atoi(s)
+	msec, _ := strconv.Atoi(ms)
+	return float64(hh)*3600 + float64(mm)*60 + float64(ss) + float64(msec)/1000
+}
+
+// WebVTTParser parses WebVTT (.vtt) caption files.
+type WebVTTParser struct{}
+
+var vttTimeRe = regexp.MustCompile(`(\d+):(\d+):(\d+)\.(\d+)\s*-->\s*(\d+):(\d+):(\d+)\.(\d+)`)
+
+func (WebVTTParser) Parse(path string) ([]LyricLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []LyricLine
+	var textBuf []string
+	var start, end float64
+	inBlock := false
+
+	flush := func() {
+		if inBlock && len(textBuf) > 0 {
+			lines = append(lines, LyricLine{Start: start, End: end, Text: strings.Join(textBuf, " ")})
+		}
+		textBuf = nil
+		inBlock = false
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "WEBVTT" {
+			continue
+		}
+		if m := vttTimeRe.FindStringSubmatch(text); m != nil {
+			flush()
+			start = srtTimestamp(m[1], m[2], m[3], m[4])
+			end = srtTimestamp(m[5], m[6], m[7], m[8])
+			inBlock = true
+			continue
+		}
+		if text == "" {
+			flush()
+			continue
+		}
+		if inBlock {
+			textBuf = append(textBuf, text)
+		}
+	}
+	flush()
+	return lines, nil
+}
+
+// ASSParser parses karaoke-tagged ASS/SSA dialogue lines, turning \k and
+// \kf override tags (centisecond word durations) into per-word timing.
+type ASSParser struct{}
+
+var (
+	assDialogueRe = regexp.MustCompile(`^Dialogue:\s*[^,]*,([^,]*),([^,]*),[^,]*,[^,]*,[^,]*,[^,]*,[^,]*,[^,]*,(.*)$`)
+	assKaraokeRe  = regexp.MustCompile(`\{\\k[f]?(\d+)\}([^{]*)`)
+)
+
+func (ASSParser) Parse(path string) ([]LyricLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []LyricLine
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		m := assDialogueRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		start, err := parseASSTimestamp(m[1])
+		if err != nil {
+			continue
+		}
+		end, err := parseASSTimestamp(m[2])
+		if err != nil {
+			continue
+		}
+
+		words := parseASSKaraoke(m[3], start)
+		text := strings.TrimSpace(joinWords(words))
+		lines = append(lines, LyricLine{Start: start, End: end, Text: text, Words: words})
+	}
+	return lines, nil
+}
+
+// parseASSTimestamp parses an ASS "h:mm:ss.cc" timestamp.
+func parseASSTimestamp(ts string) (float64, error) {
+	parts := strings.Split(strings.TrimSpace(ts), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("lyrics: malformed ASS timestamp %q", ts)
+	}
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, err
+	}
+	s, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(h)*3600 + float64(m)*60 + s, nil
+}
+
+// parseASSKaraoke walks \k/\kf override tags, each followed by the syllable
+// they time, and converts their centisecond durations into absolute word
+// start/end times anchored at lineStart.
+func parseASSKaraoke(text string, lineStart float64) []LyricWord {
+	matches := assKaraokeRe.FindAllStringSubmatch(text, -1)
+	words := make([]LyricWord, 0, len(matches))
+	t := lineStart
+	for _, km := range matches {
+		centis, err := strconv.Atoi(km[1])
+		if err != nil {
+			continue
+		}
+		word := strings.TrimSpace(km[2])
+		dur := float64(centis) / 100
+		if word != "" {
+			words = append(words, LyricWord{Start: t, End: t + dur, Text: word})
+		}
+		t += dur
+	}
+	return words
+}
+
+// parseMinSec parses the "mm" and "ss.xx" components common to LRC-family
+// timestamps into a single seconds value.
+func parseMinSec(minStr, secStr string) (float64, error) {
+	min, err := strconv.Atoi(minStr)
+	if err != nil {
+		return 0, err
+	}
+	sec, err := strconv.ParseFloat(secStr, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(min)*60 + sec, nil
+}
+
+// fillEnds sets each line's End to the next line's Start, and the last
+// line's End to five seconds past its Start.
+func fillEnds(lines []LyricLine) {
+	for i := range lines {
+		if i < len(lines)-1 {
+			lines[i].End = lines[i+1].Start
+		} else {
+			lines[i].End = lines[i].Start + 5.0
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package lyrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTemp(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLRCParser(t *testing.T) {
+	path := writeTemp(t, "song.lrc", "[00:01.00]first line\n[00:03.50]second line\n")
+
+	lines, err := LRCParser{}.Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0].Start != 1.0 || lines[0].Text != "first line" {
+		t.Errorf("line 0 = %+v", lines[0])
+	}
+	if lines[0].End != lines[1].Start {
+		t.Errorf("line 0 End = %v, want %v (next line's Start)", lines[0].End, lines[1].Start)
+	}
+}
+
+func TestEnhancedLRCParserWordTiming(t *testing.T) {
+	path := writeTemp(t, "song.lrc",
+		"[00:01.00]<00:01.00>hello<00:01.50>world<00:02.00>\n[00:05.00]next line\n")
+
+	lines, err := EnhancedLRCParser{}.Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	line := lines[0]
+	if line.Text != "helloworld" {
+		t.Errorf("Text = %q, want %q", line.Text, "helloworld")
+	}
+	if len(line.Words) != 2 {
+		t.Fatalf("got %d words, want 2: %+v", len(line.Words), line.Words)
+	}
+	if line.Words[0].Start != 1.0 || line.Words[0].End != 1.5 {
+		t.Errorf("word 0 = %+v", line.Words[0])
+	}
+	if line.Words[1].Start != 1.5 || line.Words[1].End != 5.0 {
+		t.Errorf("word 1 = %+v, want End == line's End (next line's Start)", line.Words[1])
+	}
+}
+
+func TestHasWordTagsSniffing(t *testing.T) {
+	plain := writeTemp(t, "plain.lrc", "[00:01.00]no word tags here\n")
+	if hasWordTags(plain) {
+		t.Error("plain LRC should not be sniffed as Enhanced")
+	}
+
+	enhanced := writeTemp(t, "enhanced.lrc", "[00:01.00]<00:01.00>hi\n")
+	if !hasWordTags(enhanced) {
+		t.Error("Enhanced LRC should be sniffed via its <mm:ss.xx> word tags")
+	}
+}
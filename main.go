@@ -1,21 +1,23 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
-	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/DURUII/LyricFlow/audiosrc"
+	"github.com/DURUII/LyricFlow/filtergraph"
+	"github.com/DURUII/LyricFlow/lyrics"
+	"github.com/DURUII/LyricFlow/metadata"
+	"github.com/DURUII/LyricFlow/separation"
+	"github.com/DURUII/LyricFlow/timing"
 )
 
 // LyricLine represents a lyric with start/end times.
-type LyricLine struct {
-	Start float64 // start time in seconds
-	End   float64 // end time in seconds
-	Text  string  // lyric text
-}
+type LyricLine = lyrics.LyricLine
 
 // StyleOptions holds styling and audio mix settings.
 type StyleOptions struct {
@@ -34,7 +36,30 @@ type StyleOptions struct {
 	FontPath                string // path to TTF font
 }
 
+// MixProfile selects how mergeAudioTracksWithAccompaniment handles the
+// original track's channel layout.
+type MixProfile int
+
+const (
+	// Stereo downmixes everything to plain stereo, the original behavior.
+	Stereo MixProfile = iota
+	// Surround51 preserves a >2-channel original's layout end-to-end,
+	// attenuating only its center/dialog channel and upmixing the backing
+	// track to fill the surrounds.
+	Surround51
+	// AtmosCompatible is Surround51 encoded with an Atmos-friendly codec
+	// (E-AC-3) so the result plays back correctly on Atmos-capable gear;
+	// it does not author true object-based Atmos metadata.
+	AtmosCompatible
+)
+
+var alignVAD = flag.Bool("align-vad", false, "nudge lyric timestamps onto detected vocal onsets before rendering")
+var audioProfileFlag = flag.String("audio-profile", "auto",
+	"audio mix profile: auto, stereo, surround51, or atmos")
+
 func main() {
+	flag.Parse()
+
 	// File paths
 	audioOriginal := "assets/audio/颜人中 - 晚安.mp3"
 	audioBacking := "assets/audio/颜人中 - 晚安 (伴奏).mp3"
@@ -44,12 +69,53 @@ func main() {
 
 	ensureDirs()
 
+	// Transparently decrypt encrypted containers (.ncm/.qmc*/.kgm) dropped
+	// into assets/audio/, so the rest of the pipeline only ever sees plain
+	// audio paths.
+	audioOriginal, srcMeta, err := audiosrc.Open(audioOriginal)
+	if err != nil {
+		panic(err)
+	}
+
+	// Without a pre-made backing track, synthesize one via source
+	// separation instead of requiring the user to supply "伴奏.mp3".
+	if _, statErr := os.Stat(audioBacking); audioBacking == "" || statErr != nil {
+		_, accompaniment, err := separation.SeparateStems(audioOriginal)
+		if err != nil {
+			panic(err)
+		}
+		audioBacking = accompaniment
+	} else {
+		audioBacking, _, err = audiosrc.Open(audioBacking)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// Read tag metadata straight from the file (no ffprobe shell-out); an
+	// embedded ID3 USLT lyric, if any, seeds the .lrc file when one wasn't
+	// shipped alongside the audio.
+	tagMeta, _ := metadata.Read(audioOriginal)
+	if _, err := os.Stat(lyricsFile); os.IsNotExist(err) && tagMeta.Lyrics != "" {
+		if err := os.WriteFile(lyricsFile, []byte(tagMeta.Lyrics), 0644); err != nil {
+			panic(err)
+		}
+	}
+
 	// Parse lyrics
-	allLyrics, err := parseLRC(lyricsFile)
+	allLyrics, err := lyrics.ParseFile(lyricsFile)
 	if err != nil {
 		panic(err)
 	}
 
+	// Users with slightly-off LRC timing can opt into VAD-based correction.
+	if *alignVAD {
+		allLyrics, err = timing.AlignToVAD(allLyrics, audioOriginal, timing.DefaultOptions())
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	// User selects start/end lines
 	//selected := askUserSelect(allLyrics)
 	selected := allLyrics[25:33]
@@ -57,10 +123,18 @@ func main() {
 		panic("no lyrics selected")
 	}
 
-	// Style & audio options
-	artist := extractMetadataArtist(audioOriginal)
+	// Style & audio options: prefer tags recovered straight from an
+	// encrypted container's own metadata, then fall back to the file's own
+	// ID3/Vorbis/MP4 tags.
+	title, artist := srcMeta.Title, srcMeta.Artist
+	if title == "" {
+		title = tagMeta.Title
+	}
+	if artist == "" {
+		artist = tagMeta.Artist
+	}
 	opts := StyleOptions{
-		Title:                   strings.Trim(strings.Split(extractMetadataTitle(audioOriginal), "-")[1], " 》《"),
+		Title:                   title,
 		Subtitle:                artist,
 		Bullet:                  selected[0].Text,
 		TitleColor:              "white",
@@ -80,16 +154,22 @@ func main() {
 
 	// 1) Generate background video
 	bg := "output/background.mp4"
-	generateBackground(bg, duration)
+	generateBackground(bg, duration, tagMeta.AlbumArt)
 
-	// 2) Mix audio with weighted gains
-	audioMixed := "output/audio_mixed.mp3"
+	// 2) Mix audio with weighted gains, preserving the original's surround
+	// layout (if any) instead of always downmixing to stereo.
+	mixProfile, err := resolveMixProfile(*audioProfileFlag, audioOriginal)
+	if err != nil {
+		panic(err)
+	}
+	audioMixed := "output/audio_mixed" + mixOutputExt(mixProfile)
 	mergeAudioTracksWithAccompaniment(
 		audioOriginal,
 		audioBacking,
 		selected,
 		0.2,  // 原唱低音量，比如 20%
 		0.75, // 伴奏高音量，比如 100%
+		mixProfile,
 		audioMixed,
 	)
 
@@ -111,38 +191,6 @@ func ensureDirs() {
 	os.MkdirAll("assets/fonts", 0755)
 }
 
-// parseLRC reads an .lrc file into LyricLine slices.
-func parseLRC(path string) ([]LyricLine, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	var lines []LyricLine
-	re := regexp.MustCompile(`\[(\d+):(\d+\.\d+)\](.*)`)
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		m := re.FindStringSubmatch(scanner.Text())
-		if len(m) != 4 {
-			continue
-		}
-		min, _ := strconv.Atoi(m[1])
-		sec, _ := strconv.ParseFloat(m[2], 64)
-		start := float64(min)*60 + sec
-		text := strings.TrimSpace(m[3])
-		lines = append(lines, LyricLine{Start: start, Text: text})
-	}
-	for i := range lines {
-		if i < len(lines)-1 {
-			lines[i].End = lines[i+1].Start
-		} else {
-			lines[i].End = lines[i].Start + 5.0
-		}
-	}
-	return lines, nil
-}
-
 // askUserSelect prompts for start/end line numbers.
 func askUserSelect(lyrics []LyricLine) []LyricLine {
 	fmt.Println("Available lyrics:")
@@ -158,104 +206,170 @@ func askUserSelect(lyrics []LyricLine) []LyricLine {
 	return lyrics[start-1 : end]
 }
 
-// extractMetadataArtist reads the artist tag from an MP3.
-func extractMetadataArtist(path string) string {
-	out, _ := exec.Command("ffprobe", "-v", "error",
-		"-show_entries", "format_tags=artist",
-		"-of", "default=noprint_wrappers=1", path).Output()
-	for _, line := range strings.Split(string(out), "\n") {
-		if strings.HasPrefix(line, "TAG:artist=") {
-			return strings.TrimPrefix(line, "TAG:artist=")
-		}
+// generateBackground renders a duration-long background video. When
+// albumArt is non-empty it's rendered as a blurred, crop-to-fill cover-art
+// background; otherwise it falls back to a solid black frame.
+func generateBackground(output string, duration float64, albumArt []byte) {
+	if len(albumArt) == 0 {
+		run(exec.Command("ffmpeg", "-y", "-f", "lavfi",
+			"-i", fmt.Sprintf("color=c=black:s=720x1280:d=%.2f", duration),
+			output))
+		return
 	}
-	return ""
+
+	art := "output/albumart.jpg"
+	if err := os.WriteFile(art, albumArt, 0644); err != nil {
+		panic(err)
+	}
+
+	run(exec.Command("ffmpeg", "-y",
+		"-loop", "1", "-i", art,
+		"-t", fmt.Sprintf("%.2f", duration),
+		"-vf", "scale=720:1280:force_original_aspect_ratio=increase,crop=720:1280,gblur=sigma=20",
+		output))
 }
 
-// extractMetadataTitle reads artist & title tags and formats them.
-func extractMetadataTitle(path string) string {
+// surroundLayout is the channel layout a Surround51/AtmosCompatible mix is
+// built around.
+const surroundLayout = "5.1"
+
+// resolveMixProfile applies the --audio-profile flag. "stereo",
+// "surround51", and "atmos" force the matching profile outright; "auto"
+// (the default) falls back to probing the original track's channel count.
+func resolveMixProfile(flagValue, path string) (MixProfile, error) {
+	switch strings.ToLower(flagValue) {
+	case "stereo":
+		return Stereo, nil
+	case "surround51":
+		return Surround51, nil
+	case "atmos", "atmoscompatible":
+		return AtmosCompatible, nil
+	case "auto", "":
+		return mixProfileFor(path), nil
+	default:
+		return Stereo, fmt.Errorf("unknown -audio-profile %q: want auto, stereo, surround51, or atmos", flagValue)
+	}
+}
+
+// mixProfileFor picks Surround51 for an original track that actually
+// carries more than two channels, leaving everything else on the classic
+// stereo path.
+func mixProfileFor(path string) MixProfile {
+	channels, _, err := probeChannelLayout(path)
+	if err != nil || channels <= 2 {
+		return Stereo
+	}
+	return Surround51
+}
+
+// mixOutputExt picks a container extension that can actually hold a
+// profile's output codec; stereo keeps the original .mp3.
+func mixOutputExt(profile MixProfile) string {
+	switch profile {
+	case AtmosCompatible:
+		return ".eac3"
+	case Surround51:
+		return ".m4a"
+	default:
+		return ".mp3"
+	}
+}
+
+// probeChannelLayout shells out to ffprobe for the input's channel count
+// and layout name (e.g. 6, "5.1").
+func probeChannelLayout(path string) (int, string, error) {
 	out, err := exec.Command("ffprobe", "-v", "error",
-		"-show_entries", "format_tags=artist,title",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=channels,channel_layout",
 		"-of", "default=noprint_wrappers=1", path).Output()
 	if err != nil {
-		return "《Unknown》"
+		return 0, "", err
 	}
-	artist, title := "", ""
+
+	channels, layout := 0, ""
 	for _, line := range strings.Split(string(out), "\n") {
-		if strings.HasPrefix(line, "TAG:artist=") {
-			artist = strings.TrimPrefix(line, "TAG:artist=")
+		if v, ok := strings.CutPrefix(line, "channels="); ok {
+			channels, _ = strconv.Atoi(v)
 		}
-		if strings.HasPrefix(line, "TAG:title=") {
-			title = strings.TrimPrefix(line, "TAG:title=")
+		if v, ok := strings.CutPrefix(line, "channel_layout="); ok {
+			layout = v
 		}
 	}
-	if artist != "" && title != "" {
-		return fmt.Sprintf("《%s - %s》", artist, title)
-	} else if title != "" {
-		return fmt.Sprintf("《%s》", title)
-	}
-	return "《Unknown》"
-}
-
-// generateBackground makes a solid black video of given duration.
-func generateBackground(output string, duration float64) {
-	run(exec.Command("ffmpeg", "-y", "-f", "lavfi",
-		"-i", fmt.Sprintf("color=c=black:s=720x1280:d=%.2f", duration),
-		output))
+	return channels, layout, nil
 }
 
 // mergeAudioTracksWithAccompaniment trims the first lyric segment entirely from the original track,
 // and for the remaining segments mixes the original at a low gain with the backing track at a high gain,
-// then concatenates all segments into one continuous audio stream.
+// then concatenates all segments into one continuous audio stream. For a
+// Surround51/AtmosCompatible profile, the original's full channel layout is
+// preserved: only its center/dialog channel is attenuated, and the backing
+// track is upmixed to fill the surrounds instead of being downmixed to mono
+// dialog replacement.
 func mergeAudioTracksWithAccompaniment(orig, back string, sel []LyricLine,
-	origGain, backGain float64, output string) {
-	// Build filter_complex dynamically
-	var filters []string
-	// 1) First segment: original only
-	first := sel[0]
-	filters = append(filters,
-		fmt.Sprintf(
-			"[0:a]atrim=%.2f:%.2f,asetpts=PTS-STARTPTS,volume=%.2f[first]",
-			first.Start, first.End, 1.0, // keep full volume for first line
-		))
+	origGain, backGain float64, profile MixProfile, output string) {
+	g := filtergraph.NewGraph()
+	origIn, backIn := g.Input("0:a"), g.Input("1:a")
+
+	buildSegment := func(ln LyricLine, dialogGain float64) filtergraph.Stream {
+		origTrim := g.Apply(filtergraph.Chain(filtergraph.ATrim(ln.Start, ln.End), filtergraph.SetPTS()), origIn)
+		if profile == Stereo {
+			origSeg := g.Apply(filtergraph.Volume(dialogGain), origTrim)
+			if dialogGain == 1.0 {
+				return origSeg
+			}
+			backSeg := g.Apply(filtergraph.Chain(
+				filtergraph.ATrim(ln.Start, ln.End),
+				filtergraph.SetPTS(),
+				filtergraph.Volume(backGain),
+			), backIn)
+			return g.Apply(filtergraph.AMix(2), backSeg, origSeg)
+		}
+
+		// Attenuate only the center channel, where vocals typically sit,
+		// leaving the rest of the original's bed untouched.
+		origAttenuated := g.Apply(filtergraph.Pan(surroundLayout,
+			"FL=FL", "FR=FR", fmt.Sprintf("FC=%.2f*FC", dialogGain), "LFE=LFE", "BL=BL", "BR=BR",
+		), origTrim)
+		if dialogGain == 1.0 {
+			return origAttenuated
+		}
+
+		backTrim := g.Apply(filtergraph.Chain(
+			filtergraph.ATrim(ln.Start, ln.End),
+			filtergraph.SetPTS(),
+			filtergraph.Volume(backGain),
+		), backIn)
+		backSurround := g.Apply(filtergraph.Surround(surroundLayout), backTrim)
+		return g.Apply(filtergraph.AMix(2), origAttenuated, backSurround)
+	}
+
+	// 1) First segment: original only, full volume
+	segs := []filtergraph.Stream{buildSegment(sel[0], 1.0)}
+
 	// 2) Remaining segments: mix original(low) + backing(high)
-	for i := 1; i < len(sel); i++ {
-		ln := sel[i]
-		// trim backing
-		filters = append(filters,
-			fmt.Sprintf(
-				"[1:a]atrim=%.2f:%.2f,asetpts=PTS-STARTPTS,volume=%.2f[back%d]",
-				ln.Start, ln.End, backGain, i))
-		// trim original
-		filters = append(filters,
-			fmt.Sprintf(
-				"[0:a]atrim=%.2f:%.2f,asetpts=PTS-STARTPTS,volume=%.2f[orig%d]",
-				ln.Start, ln.End, origGain, i))
-		// amix the two
-		filters = append(filters,
-			fmt.Sprintf(
-				"[back%d][orig%d]amix=inputs=2:duration=first[seg%d]",
-				i, i, i))
+	for _, ln := range sel[1:] {
+		segs = append(segs, buildSegment(ln, origGain))
 	}
-	// 3) Concatenate all segments: first + seg1 + seg2 + ...
-	var segNames []string
-	segNames = append(segNames, "[first]")
-	for i := 1; i < len(sel); i++ {
-		segNames = append(segNames, fmt.Sprintf("[seg%d]", i))
+
+	// 3) Concatenate all segments into one continuous track
+	out := g.Named("out")
+	g.ApplyTo(out, filtergraph.Concat(len(segs), 0, 1), segs...)
+
+	args := []string{"-y", "-i", orig, "-i", back,
+		"-filter_complex", g.Compile(),
+		"-map", out.Label(),
 	}
-	filters = append(filters,
-		strings.Join(segNames, "")+
-			fmt.Sprintf("concat=n=%d:v=0:a=1[out]", len(segNames)),
-	)
+	switch profile {
+	case AtmosCompatible:
+		args = append(args, "-c:a", "eac3", "-ac", "6")
+	case Surround51:
+		args = append(args, "-c:a", "aac", "-ac", "6")
+	default:
+		args = append(args, "-acodec", "libmp3lame")
+	}
+	args = append(args, output)
 
-	cmd := exec.Command("ffmpeg", "-y",
-		"-i", orig,
-		"-i", back,
-		"-filter_complex", strings.Join(filters, ";"),
-		"-map", "[out]",
-		"-acodec", "libmp3lame",
-		output,
-	)
-	run(cmd)
+	run(exec.Command("ffmpeg", args...))
 }
 
 func generateLyricsVideoNotesStyle(
@@ -265,34 +379,21 @@ func generateLyricsVideoNotesStyle(
 	offset float64,
 	output string,
 ) {
-	var filterParts []string
 	inputs := []string{
 		"-i", background, // 0:v 背景
 		"-i", "assets/img/选择.png", // 1:v 未选中
 		"-i", "assets/img/选择-勾选.png", // 2:v 已勾选
 	}
 
-	// 起始流
-	current := "[0:v]"
-	baseIdx := 0
+	g := filtergraph.NewGraph()
+	bgIn, uncheckedIn, checkedIn := g.Input("0:v"), g.Input("1:v"), g.Input("2:v")
 
 	startY, lineH := 340, 72
 
 	// Step 1：添加标题、子标题、Bullet
-	filterParts = append(filterParts, fmt.Sprintf(
-		"%sdrawtext=fontfile=%s:text='%s':fontsize=48:fontcolor=%s:x=(w-text_w)/2:y=100[base%d]",
-		current, opts.FontPath, escape(opts.Title), opts.TitleColor, baseIdx+1))
-	current = fmt.Sprintf("[base%d]", baseIdx+1)
-
-	filterParts = append(filterParts, fmt.Sprintf(
-		"%sdrawtext=fontfile=%s:text='-%s':fontsize=36:fontcolor=%s:x=(w-text_w)/2:y=180[base%d]",
-		current, opts.FontPath, escape(opts.Subtitle), opts.SubtitleColor, baseIdx+2))
-	current = fmt.Sprintf("[base%d]", baseIdx+2)
-
-	filterParts = append(filterParts, fmt.Sprintf(
-		"%sdrawtext=fontfile=%s:text='• %s':fontsize=36:fontcolor=%s:x=60:y=260[base%d]",
-		current, opts.FontPath, escape(opts.Bullet), opts.BulletColor, baseIdx+3))
-	current = fmt.Sprintf("[base%d]", baseIdx+3)
+	current := g.Apply(filtergraph.DrawText(opts.FontPath, escape(opts.Title), 48, opts.TitleColor, "(w-text_w)/2", "100"), bgIn)
+	current = g.Apply(filtergraph.DrawText(opts.FontPath, escape("-"+opts.Subtitle), 36, opts.SubtitleColor, "(w-text_w)/2", "180"), current)
+	current = g.Apply(filtergraph.DrawText(opts.FontPath, escape("• "+opts.Bullet), 36, opts.BulletColor, "60", "260"), current)
 
 	// Step 2：Checklist每行
 	for i, ln := range lyrics[1:] {
@@ -301,48 +402,30 @@ func generateLyricsVideoNotesStyle(
 		end := ln.End - offset
 
 		// 缩放未选中图并叠加
-		filterParts = append(filterParts, fmt.Sprintf(
-			"[1:v]scale=40:40[unchecked%d];%s[unchecked%d]overlay=x=60:y=%d:enable='lt(t,%.2f)'[base%d]",
-			i, current, i, y, start, baseIdx+4+i*5,
-		))
-		current = fmt.Sprintf("[base%d]", baseIdx+4+i*5)
+		uncheckedScaled := g.Apply(filtergraph.Scale(40, 40), uncheckedIn)
+		current = g.Apply(filtergraph.Overlay(60, y, fmt.Sprintf("lt(t,%.2f)", start)), current, uncheckedScaled)
 
 		// 缩放已选中图并叠加
-		filterParts = append(filterParts, fmt.Sprintf(
-			"[2:v]scale=40:40[checked%d];%s[checked%d]overlay=x=60:y=%d:enable='gte(t,%.2f)'[base%d]",
-			i, current, i, y, start, baseIdx+5+i*5,
-		))
-		current = fmt.Sprintf("[base%d]", baseIdx+5+i*5)
+		checkedScaled := g.Apply(filtergraph.Scale(40, 40), checkedIn)
+		current = g.Apply(filtergraph.Overlay(60, y, fmt.Sprintf("gte(t,%.2f)", start)), current, checkedScaled)
 
 		// 未唱到（灰色）
-		filterParts = append(filterParts, fmt.Sprintf(
-			"%sdrawtext=fontfile=%s:text='%s':fontsize=36:fontcolor=%s:x=120:y=%d:enable='lt(t,%.2f)'[base%d]",
-			current, opts.FontPath, escape(ln.Text), opts.NormalColor, y+4, start, baseIdx+6+i*5,
-		))
-		current = fmt.Sprintf("[base%d]", baseIdx+6+i*5)
-
-		// 正在唱（高亮色）
-		filterParts = append(filterParts, fmt.Sprintf(
-			"%sdrawtext=fontfile=%s:text='%s':fontsize=36:fontcolor=%s:x=120:y=%d:enable='between(t,%.2f,%.2f)'[base%d]",
-			current, opts.FontPath, escape(ln.Text), opts.HighlightColor, y+4, start, end, baseIdx+7+i*5,
-		))
-		current = fmt.Sprintf("[base%d]", baseIdx+7+i*5)
+		current = g.Apply(filtergraph.DrawTextEnabled(opts.FontPath, escape(ln.Text), 36, opts.NormalColor,
+			"120", fmt.Sprintf("%d", y+4), fmt.Sprintf("lt(t,%.2f)", start)), current)
+
+		// 正在唱（高亮色）：逐字/逐词高亮，若有逐词时间则渐进推进，否则整行一起高亮
+		current = drawSingingNow(g, current, opts, ln, y+4, start, end)
 
 		// 唱完（白色）
-		filterParts = append(filterParts, fmt.Sprintf(
-			"%sdrawtext=fontfile=%s:text='%s':fontsize=36:fontcolor=%s:x=120:y=%d:enable='gt(t,%.2f)'[base%d]",
-			current, opts.FontPath, escape(ln.Text), opts.FinishedColor, y+4, end, baseIdx+8+i*5,
-		))
-		current = fmt.Sprintf("[base%d]", baseIdx+8+i*5)
+		current = g.Apply(filtergraph.DrawTextEnabled(opts.FontPath, escape(ln.Text), 36, opts.FinishedColor,
+			"120", fmt.Sprintf("%d", y+4), fmt.Sprintf("gt(t,%.2f)", end)), current)
 	}
 
-	final := current
-
 	cmdArgs := []string{"-y"}
 	cmdArgs = append(cmdArgs, inputs...)
 	cmdArgs = append(cmdArgs,
-		"-filter_complex", strings.Join(filterParts, ";"),
-		"-map", final,
+		"-filter_complex", g.Compile(),
+		"-map", current.Label(),
 		"-c:v", "libx264", "-preset", "fast", "-crf", "18",
 		output,
 	)
@@ -351,6 +434,36 @@ func generateLyricsVideoNotesStyle(
 	run(cmd)
 }
 
+// drawSingingNow overlays the line currently being sung. When ln.Words
+// carries per-word timing it draws each word separately so highlighting
+// advances word-by-word (karaoke style); otherwise the whole line highlights
+// at once, as before. x offsets between words are estimated from glyph
+// count since drawtext has no way to measure a sibling node's rendered
+// width.
+func drawSingingNow(g *filtergraph.Graph, current filtergraph.Stream, opts StyleOptions, ln LyricLine, y int, start, end float64) filtergraph.Stream {
+	if len(ln.Words) == 0 {
+		return g.Apply(filtergraph.DrawTextEnabled(opts.FontPath, escape(ln.Text), 36, opts.HighlightColor,
+			"120", fmt.Sprintf("%d", y), fmt.Sprintf("between(t,%.2f,%.2f)", start, end)), current)
+	}
+
+	const fontSize = 36
+	x := 120
+	for _, w := range ln.Words {
+		wordStart, wordEnd := w.Start-ln.Start+start, w.End-ln.Start+start
+		current = g.Apply(filtergraph.DrawTextEnabled(opts.FontPath, escape(w.Text), fontSize, opts.HighlightColor,
+			fmt.Sprintf("%d", x), fmt.Sprintf("%d", y), fmt.Sprintf("between(t,%.2f,%.2f)", wordStart, wordEnd)), current)
+		x += estimateTextWidth(w.Text, fontSize)
+	}
+	return current
+}
+
+// estimateTextWidth approximates the rendered pixel width of text at
+// fontSize, since ffmpeg's drawtext gives sibling filters no way to query
+// another node's actual text_w.
+func estimateTextWidth(text string, fontSize int) int {
+	return len([]rune(text)) * fontSize * 6 / 10
+}
+
 // composeFinalVideo merges the video (no audio) with the mixed audio file.
 func composeFinalVideo(video, audio, output string) {
 	cmd := exec.Command("ffmpeg", "-y",
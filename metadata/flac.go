@@ -0,0 +1,124 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	flacBlockVorbisComment = 4
+	flacBlockPicture       = 6
+)
+
+// readFLAC reads Metadata from a FLAC file's VORBIS_COMMENT and PICTURE
+// metadata blocks.
+func readFLAC(path string) (Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer f.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return Metadata{}, err
+	}
+	if string(magic) != "fLaC" {
+		return Metadata{}, io.ErrUnexpectedEOF
+	}
+
+	var meta Metadata
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(f, header); err != nil {
+			return meta, nil
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7f
+		size := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+
+		block := make([]byte, size)
+		if _, err := io.ReadFull(f, block); err != nil {
+			return meta, nil
+		}
+
+		switch blockType {
+		case flacBlockVorbisComment:
+			applyVorbisComments(&meta, block)
+		case flacBlockPicture:
+			meta.AlbumArt = parseFLACPicture(block)
+		}
+
+		if last {
+			break
+		}
+	}
+	return meta, nil
+}
+
+// applyVorbisComments decodes a VORBIS_COMMENT block and copies the
+// TITLE/ARTIST/ALBUM fields (case-insensitive keys) into meta.
+func applyVorbisComments(meta *Metadata, block []byte) {
+	if len(block) < 4 {
+		return
+	}
+	vendorLen := int(binary.LittleEndian.Uint32(block[0:4]))
+	off := 4 + vendorLen
+	if off+4 > len(block) {
+		return
+	}
+	count := int(binary.LittleEndian.Uint32(block[off : off+4]))
+	off += 4
+
+	for i := 0; i < count && off+4 <= len(block); i++ {
+		entryLen := int(binary.LittleEndian.Uint32(block[off : off+4]))
+		off += 4
+		if off+entryLen > len(block) {
+			return
+		}
+		entry := string(block[off : off+entryLen])
+		off += entryLen
+
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(key) {
+		case "TITLE":
+			meta.Title = value
+		case "ARTIST":
+			meta.Artist = value
+		case "ALBUM":
+			meta.Album = value
+		}
+	}
+}
+
+// parseFLACPicture extracts the embedded image bytes from a PICTURE block.
+func parseFLACPicture(block []byte) []byte {
+	off := 4 // picture type
+	if off+4 > len(block) {
+		return nil
+	}
+	mimeLen := int(binary.BigEndian.Uint32(block[off : off+4]))
+	off += 4 + mimeLen
+
+	if off+4 > len(block) {
+		return nil
+	}
+	descLen := int(binary.BigEndian.Uint32(block[off : off+4]))
+	off += 4 + descLen
+
+	off += 16 // width, height, depth, colors used (4 bytes each)
+	if off+4 > len(block) {
+		return nil
+	}
+	dataLen := int(binary.BigEndian.Uint32(block[off : off+4]))
+	off += 4
+	if off+dataLen > len(block) {
+		return nil
+	}
+	return block[off : off+dataLen]
+}
@@ -0,0 +1,68 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// vorbisCommentBlock builds a minimal VORBIS_COMMENT payload with the given
+// "KEY=value" entries and no vendor string.
+func vorbisCommentBlock(entries ...string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // empty vendor
+	binary.Write(&buf, binary.LittleEndian, uint32(len(entries)))
+	for _, e := range entries {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(e)))
+		buf.WriteString(e)
+	}
+	return buf.Bytes()
+}
+
+// flacMetadataBlock wraps a block's payload with its FLAC metadata-block
+// header (last-block flag, type, 24-bit size).
+func flacMetadataBlock(blockType byte, last bool, payload []byte) []byte {
+	var header [4]byte
+	header[0] = blockType
+	if last {
+		header[0] |= 0x80
+	}
+	size := len(payload)
+	header[1] = byte(size >> 16)
+	header[2] = byte(size >> 8)
+	header[3] = byte(size)
+	return append(header[:], payload...)
+}
+
+func TestReadFLACVorbisComment(t *testing.T) {
+	comment := vorbisCommentBlock("TITLE=Test Title", "ARTIST=Test Artist")
+
+	var file bytes.Buffer
+	file.WriteString("fLaC")
+	file.Write(flacMetadataBlock(flacBlockVorbisComment, true, comment))
+
+	path := filepath.Join(t.TempDir(), "song.flac")
+	if err := os.WriteFile(path, file.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := readFLAC(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Test Title" || meta.Artist != "Test Artist" {
+		t.Errorf("got %+v", meta)
+	}
+}
+
+func TestReadFLACRejectsBadMagic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "song.flac")
+	if err := os.WriteFile(path, []byte("nope"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readFLAC(path); err == nil {
+		t.Error("expected an error for a non-FLAC file")
+	}
+}
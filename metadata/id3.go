@@ -0,0 +1,240 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"unicode/utf16"
+)
+
+// readID3 reads Metadata from an MP3's ID3v2 header, falling back to the
+// fixed-layout ID3v1 trailer for title/artist/album when no ID3v2 tag (or
+// none of the frames we understand) is present.
+func readID3(path string) (Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer f.Close()
+
+	meta, err := readID3v2(f)
+	if err != nil {
+		return Metadata{}, err
+	}
+	if meta.Title != "" && meta.Artist != "" {
+		return meta, nil
+	}
+
+	v1, err := readID3v1(f)
+	if err != nil {
+		return meta, nil // ID3v2 partial result is still useful
+	}
+	if meta.Title == "" {
+		meta.Title = v1.Title
+	}
+	if meta.Artist == "" {
+		meta.Artist = v1.Artist
+	}
+	if meta.Album == "" {
+		meta.Album = v1.Album
+	}
+	return meta, nil
+}
+
+// readID3v2 parses an ID3v2.3/2.4 header and the frames we care about:
+// TIT2/TPE1/TALB for tags, APIC for cover art, USLT for embedded lyrics.
+func readID3v2(f *os.File) (Metadata, error) {
+	var meta Metadata
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return meta, nil // too short to hold a tag; not an error
+	}
+	if string(header[:3]) != "ID3" {
+		return meta, nil
+	}
+	majorVersion := header[3]
+	tagSize := synchsafe(header[6:10])
+
+	body := make([]byte, tagSize)
+	if _, err := io.ReadFull(f, body); err != nil {
+		return meta, nil
+	}
+
+	for off := 0; off+10 <= len(body); {
+		id := string(body[off : off+4])
+		if id == "\x00\x00\x00\x00" {
+			break // padding
+		}
+
+		var size int
+		if majorVersion >= 4 {
+			size = synchsafe(body[off+4 : off+8])
+		} else {
+			size = int(binary.BigEndian.Uint32(body[off+4 : off+8]))
+		}
+		frameStart := off + 10
+		frameEnd := frameStart + size
+		if size < 0 || frameEnd > len(body) {
+			break
+		}
+		frame := body[frameStart:frameEnd]
+
+		switch id {
+		case "TIT2":
+			meta.Title = decodeID3Text(frame)
+		case "TPE1":
+			meta.Artist = decodeID3Text(frame)
+		case "TALB":
+			meta.Album = decodeID3Text(frame)
+		case "APIC":
+			meta.AlbumArt = decodeAPIC(frame)
+		case "USLT":
+			meta.Lyrics = decodeUSLT(frame)
+		}
+
+		off = frameEnd
+	}
+
+	return meta, nil
+}
+
+// synchsafe decodes a 4-byte ID3v2 synchsafe integer (7 usable bits/byte).
+func synchsafe(b []byte) int {
+	return int(b[0])<<21 | int(b[1])<<14 | int(b[2])<<7 | int(b[3])
+}
+
+// decodeID3Text decodes an ID3v2 text-information frame body: a one-byte
+// encoding marker followed by the (possibly null-terminated) text.
+func decodeID3Text(frame []byte) string {
+	if len(frame) < 1 {
+		return ""
+	}
+	return decodeID3String(frame[0], frame[1:])
+}
+
+// decodeID3String decodes raw ID3v2 string bytes per the given encoding
+// byte (0=ISO-8859-1, 1=UTF-16 with BOM, 2=UTF-16BE, 3=UTF-8).
+func decodeID3String(encoding byte, raw []byte) string {
+	switch encoding {
+	case 1, 2:
+		return decodeUTF16(raw)
+	case 0:
+		raw = bytes.TrimRight(raw, "\x00")
+		runes := make([]rune, len(raw))
+		for i, c := range raw {
+			runes[i] = rune(c) // ISO-8859-1 bytes are their own code points
+		}
+		return string(runes)
+	default:
+		return string(bytes.TrimRight(raw, "\x00"))
+	}
+}
+
+func decodeUTF16(raw []byte) string {
+	// Strip whole 2-byte null terminators only, so a final little-endian
+	// unit's zero high byte isn't mistaken for padding.
+	for len(raw) >= 2 && raw[len(raw)-2] == 0 && raw[len(raw)-1] == 0 {
+		raw = raw[:len(raw)-2]
+	}
+	if len(raw) < 2 {
+		return ""
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if raw[0] == 0xFF && raw[1] == 0xFE {
+		order = binary.LittleEndian
+		raw = raw[2:]
+	} else if raw[0] == 0xFE && raw[1] == 0xFF {
+		raw = raw[2:]
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = order.Uint16(raw[i*2 : i*2+2])
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodeAPIC pulls the raw picture bytes out of an APIC frame body:
+// encoding(1) + MIME(null-term) + picture type(1) + description(null-term) + data.
+func decodeAPIC(frame []byte) []byte {
+	if len(frame) < 2 {
+		return nil
+	}
+	encoding := frame[0]
+	rest := frame[1:]
+
+	mimeEnd := bytes.IndexByte(rest, 0x00)
+	if mimeEnd < 0 {
+		return nil
+	}
+	rest = rest[mimeEnd+1:]
+	if len(rest) < 1 {
+		return nil
+	}
+	rest = rest[1:] // picture type
+
+	descEnd := indexStringTerminator(rest, encoding)
+	if descEnd < 0 {
+		return nil
+	}
+	return rest[descEnd:]
+}
+
+// decodeUSLT decodes a USLT frame: encoding(1) + language(3) +
+// description(null-term) + lyrics.
+func decodeUSLT(frame []byte) string {
+	if len(frame) < 4 {
+		return ""
+	}
+	encoding := frame[0]
+	rest := frame[4:] // skip the 3-byte language code
+
+	descEnd := indexStringTerminator(rest, encoding)
+	if descEnd < 0 {
+		return ""
+	}
+	return decodeID3String(encoding, rest[descEnd:])
+}
+
+// indexStringTerminator finds the end of a null-terminated string (one null
+// byte for single-byte encodings, two for UTF-16) and returns the offset
+// just past the terminator.
+func indexStringTerminator(b []byte, encoding byte) int {
+	if encoding == 1 || encoding == 2 {
+		for i := 0; i+1 < len(b); i += 2 {
+			if b[i] == 0 && b[i+1] == 0 {
+				return i + 2
+			}
+		}
+		return -1
+	}
+	i := bytes.IndexByte(b, 0x00)
+	if i < 0 {
+		return -1
+	}
+	return i + 1
+}
+
+// readID3v1 reads the fixed-layout 128-byte ID3v1 trailer.
+func readID3v1(f *os.File) (Metadata, error) {
+	if _, err := f.Seek(-128, io.SeekEnd); err != nil {
+		return Metadata{}, err
+	}
+	tag := make([]byte, 128)
+	if _, err := io.ReadFull(f, tag); err != nil {
+		return Metadata{}, err
+	}
+	if string(tag[:3]) != "TAG" {
+		return Metadata{}, io.ErrUnexpectedEOF
+	}
+	return Metadata{
+		Title:  trimID3v1(tag[3:33]),
+		Artist: trimID3v1(tag[33:63]),
+		Album:  trimID3v1(tag[63:93]),
+	}, nil
+}
+
+func trimID3v1(b []byte) string {
+	return string(bytes.TrimRight(b, " \x00"))
+}
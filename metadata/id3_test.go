@@ -0,0 +1,90 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// synchsafeEncode is the inverse of synchsafe, used to build a test fixture.
+func synchsafeEncode(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+}
+
+// id3v2Frame builds a single ID3v2.3 frame: id(4) + size(4, big-endian) +
+// flags(2) + body.
+func id3v2Frame(id string, body []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(id)
+	binary.Write(&buf, binary.BigEndian, uint32(len(body)))
+	buf.Write([]byte{0, 0}) // flags
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+func writeID3v2Fixture(t *testing.T) string {
+	t.Helper()
+
+	frames := append(
+		id3v2Frame("TIT2", append([]byte{0}, []byte("Test Title")...)),
+		id3v2Frame("TPE1", append([]byte{0}, []byte("Test Artist")...))...,
+	)
+
+	var tag bytes.Buffer
+	tag.WriteString("ID3")
+	tag.Write([]byte{3, 0, 0}) // version 2.3, flags
+	tag.Write(synchsafeEncode(len(frames)))
+	tag.Write(frames)
+
+	path := filepath.Join(t.TempDir(), "song.mp3")
+	if err := os.WriteFile(path, tag.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadID3v2(t *testing.T) {
+	path := writeID3v2Fixture(t)
+
+	meta, err := readID3(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Test Title" {
+		t.Errorf("Title = %q, want %q", meta.Title, "Test Title")
+	}
+	if meta.Artist != "Test Artist" {
+		t.Errorf("Artist = %q, want %q", meta.Artist, "Test Artist")
+	}
+}
+
+func TestReadID3v1Fallback(t *testing.T) {
+	var tag [128]byte
+	copy(tag[0:3], "TAG")
+	copy(tag[3:33], "V1 Title")
+	copy(tag[33:63], "V1 Artist")
+	copy(tag[63:93], "V1 Album")
+
+	path := filepath.Join(t.TempDir(), "song.mp3")
+	if err := os.WriteFile(path, tag[:], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := readID3(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "V1 Title" || meta.Artist != "V1 Artist" || meta.Album != "V1 Album" {
+		t.Errorf("got %+v", meta)
+	}
+}
+
+func TestDecodeUTF16(t *testing.T) {
+	// "Hi" as UTF-16BE code units, no BOM.
+	raw := []byte{0x00, 'H', 0x00, 'i'}
+	if got := decodeUTF16(raw); got != "Hi" {
+		t.Errorf("decodeUTF16 = %q, want %q", got, "Hi")
+	}
+}
@@ -0,0 +1,49 @@
+// Package metadata reads audio tag metadata (ID3, Vorbis comments, MP4
+// atoms) without shelling out to ffprobe.
+package metadata
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Metadata is the tag information callers need to caption and title a
+// generated video.
+type Metadata struct {
+	Title    string
+	Artist   string
+	Album    string
+	AlbumArt []byte
+	Duration float64 // seconds; zero when the backend doesn't report it
+	Lyrics   string  // plain text from an embedded ID3 USLT frame, if any; SYLT frames are not read
+}
+
+// TagReader reads Metadata from an audio file.
+type TagReader interface {
+	Read(path string) (Metadata, error)
+}
+
+// Read reads path with the default pure-Go backend, dispatching to the
+// ID3, FLAC or MP4 reader by file extension.
+func Read(path string) (Metadata, error) {
+	return defaultReader{}.Read(path)
+}
+
+// defaultReader is the built-in, pure-Go TagReader. A cgo taglib-backed
+// TagReader is available behind the "taglib" build tag for formats this
+// one doesn't cover.
+type defaultReader struct{}
+
+func (defaultReader) Read(path string) (Metadata, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mp3":
+		return readID3(path)
+	case ".flac":
+		return readFLAC(path)
+	case ".m4a", ".mp4", ".m4b":
+		return readMP4(path)
+	default:
+		return Metadata{}, fmt.Errorf("metadata: unsupported format %q", filepath.Ext(path))
+	}
+}
@@ -0,0 +1,147 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// readMP4 reads Metadata from an MP4/M4A file's moov/udta/meta/ilst atom
+// tree.
+func readMP4(path string) (Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer f.Close()
+
+	moov, err := findMP4Atom(f, "moov", atomRange{0, mp4FileSize(f)})
+	if err != nil || moov == nil {
+		return Metadata{}, err
+	}
+	udta, err := findMP4Atom(f, "udta", *moov)
+	if err != nil || udta == nil {
+		return Metadata{}, nil
+	}
+	meta, err := findMP4Atom(f, "meta", *udta)
+	if err != nil || meta == nil {
+		return Metadata{}, nil
+	}
+	// The "meta" box is a full box: 4 bytes of version/flags before its
+	// children start.
+	ilst, err := findMP4Atom(f, "ilst", atomRange{meta.start + 4, meta.end})
+	if err != nil || ilst == nil {
+		return Metadata{}, nil
+	}
+
+	var out Metadata
+	children, err := mp4Children(f, *ilst)
+	if err != nil {
+		return Metadata{}, nil
+	}
+	for _, c := range children {
+		data, err := findMP4Atom(f, "data", c.rng)
+		if err != nil || data == nil {
+			continue
+		}
+		value, err := readMP4DataAtom(f, *data)
+		if err != nil {
+			continue
+		}
+		switch c.kind {
+		case "\xa9nam":
+			out.Title = string(value)
+		case "\xa9ART":
+			out.Artist = string(value)
+		case "\xa9alb":
+			out.Album = string(value)
+		case "covr":
+			out.AlbumArt = value
+		}
+	}
+	return out, nil
+}
+
+// atomRange is the byte span [start, end) of an MP4 box's payload.
+type atomRange struct {
+	start int64
+	end   int64
+}
+
+type mp4Child struct {
+	kind string
+	rng  atomRange
+}
+
+func mp4FileSize(f *os.File) int64 {
+	info, err := f.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// findMP4Atom scans the direct children within rng for one with the given
+// four-character type and returns its payload range.
+func findMP4Atom(f *os.File, kind string, rng atomRange) (*atomRange, error) {
+	children, err := mp4Children(f, rng)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range children {
+		if c.kind == kind {
+			r := c.rng
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+// mp4Children walks the box headers directly within rng.
+func mp4Children(f *os.File, rng atomRange) ([]mp4Child, error) {
+	var children []mp4Child
+	pos := rng.start
+	for pos+8 <= rng.end {
+		header := make([]byte, 8)
+		if _, err := f.ReadAt(header, pos); err != nil {
+			return children, nil
+		}
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		kind := string(header[4:8])
+
+		headerLen := int64(8)
+		if size == 1 {
+			ext := make([]byte, 8)
+			if _, err := f.ReadAt(ext, pos+8); err != nil {
+				return children, nil
+			}
+			size = int64(binary.BigEndian.Uint64(ext))
+			headerLen = 16
+		} else if size == 0 {
+			size = rng.end - pos
+		}
+		if size < headerLen || pos+size > rng.end {
+			break
+		}
+
+		children = append(children, mp4Child{
+			kind: kind,
+			rng:  atomRange{pos + headerLen, pos + size},
+		})
+		pos += size
+	}
+	return children, nil
+}
+
+// readMP4DataAtom reads an iTunes-style "data" atom: 4-byte type indicator +
+// 4-byte locale, then the value.
+func readMP4DataAtom(f *os.File, rng atomRange) ([]byte, error) {
+	if rng.end-rng.start < 8 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	value := make([]byte, rng.end-rng.start-8)
+	if _, err := f.ReadAt(value, rng.start+8); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,65 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// mp4Box builds an MP4 box: 4-byte size (header plus payload) + 4-byte type
+// + payload.
+func mp4Box(kind string, payload []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(8+len(payload)))
+	buf.WriteString(kind)
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+// mp4DataAtom builds an iTunes-style "data" atom: type indicator(4) +
+// locale(4) + value.
+func mp4DataAtom(value string) []byte {
+	payload := append([]byte{0, 0, 0, 1, 0, 0, 0, 0}, []byte(value)...)
+	return mp4Box("data", payload)
+}
+
+func TestReadMP4Tags(t *testing.T) {
+	title := mp4Box("\xa9nam", mp4DataAtom("Test Title"))
+	artist := mp4Box("\xa9ART", mp4DataAtom("Test Artist"))
+	ilst := mp4Box("ilst", append(title, artist...))
+
+	meta := append([]byte{0, 0, 0, 0}, ilst...) // meta is a full box
+	metaBox := mp4Box("meta", meta)
+	udtaBox := mp4Box("udta", metaBox)
+	moovBox := mp4Box("moov", udtaBox)
+
+	path := filepath.Join(t.TempDir(), "song.m4a")
+	if err := os.WriteFile(path, moovBox, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readMP4(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Test Title" || got.Artist != "Test Artist" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestReadMP4NoMoov(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "song.m4a")
+	if err := os.WriteFile(path, mp4Box("free", nil), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readMP4(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "" || got.Artist != "" || got.Album != "" || got.AlbumArt != nil {
+		t.Errorf("got %+v, want zero value", got)
+	}
+}
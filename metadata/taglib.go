@@ -0,0 +1,51 @@
+//go:build taglib
+
+package metadata
+
+/*
+#cgo pkg-config: taglib_c
+#include <stdlib.h>
+#include <tag_c.h>
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// TagLibReader reads Metadata via libtag, covering the long tail of
+// container/codec combinations (WMA, APE, WavPack, ...) the pure-Go readers
+// don't. Build with `-tags taglib` once libtag-dev/taglib_c is installed.
+type TagLibReader struct{}
+
+func (TagLibReader) Read(path string) (Metadata, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	file := C.taglib_file_new(cpath)
+	if file == nil {
+		return Metadata{}, fmt.Errorf("metadata: taglib could not open %q", path)
+	}
+	defer C.taglib_file_free(file)
+
+	tag := C.taglib_file_tag(file)
+	props := C.taglib_file_audioproperties(file)
+
+	meta := Metadata{
+		Title:  goString(C.taglib_tag_title(tag)),
+		Artist: goString(C.taglib_tag_artist(tag)),
+		Album:  goString(C.taglib_tag_album(tag)),
+	}
+	if props != nil {
+		meta.Duration = float64(C.taglib_audioproperties_length(props))
+	}
+	return meta, nil
+}
+
+func goString(s *C.char) string {
+	if s == nil {
+		return ""
+	}
+	defer C.taglib_free(unsafe.Pointer(s))
+	return C.GoString(s)
+}
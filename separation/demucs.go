@@ -0,0 +1,42 @@
+package separation
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DemucsRemover separates stems via Meta's Demucs, the highest-quality
+// backend we support.
+type DemucsRemover struct {
+	Model string // defaults to "htdemucs" when empty
+}
+
+func (DemucsRemover) binary() string { return "demucs" }
+
+func (d DemucsRemover) Separate(path, vocalsOut, accompanimentOut string) error {
+	model := d.Model
+	if model == "" {
+		model = "htdemucs"
+	}
+
+	tmp, err := os.MkdirTemp("", "lyricflow-demucs-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := run(exec.Command("demucs", "--two-stems=vocals", "-n", model, "-o", tmp, path)); err != nil {
+		return err
+	}
+
+	// Demucs writes <out>/<model>/<source-stem>/{vocals,no_vocals}.wav.
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	outDir := filepath.Join(tmp, model, stem)
+
+	if err := transcode(filepath.Join(outDir, "vocals.wav"), vocalsOut); err != nil {
+		return err
+	}
+	return transcode(filepath.Join(outDir, "no_vocals.wav"), accompanimentOut)
+}
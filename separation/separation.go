@@ -0,0 +1,107 @@
+// Package separation synthesizes a backing track from a single audio file
+// via source separation, for users who don't have a pre-made accompaniment
+// track to drop into assets/audio/.
+package separation
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// VocalRemover splits an audio file into a vocals stem and an
+// accompaniment (instrumental) stem.
+type VocalRemover interface {
+	Separate(path, vocalsOut, accompanimentOut string) error
+}
+
+// cacheDir holds previously separated stems, keyed by source file so
+// repeated runs against the same audio skip the (slow) separation step.
+const cacheDir = "output/separated"
+
+// SeparateStems splits path into vocal and accompaniment tracks using the
+// first available backend (Demucs, then Spleeter, then the ONNX UVR
+// fallback), caching the result under output/separated/<hash>.{mp3,vocals.mp3}.
+func SeparateStems(path string) (vocals, accompaniment string, err error) {
+	return SeparateStemsWith(defaultRemover(), path)
+}
+
+// SeparateStemsWith is SeparateStems with an explicit VocalRemover, so
+// callers (and tests) can pick a specific backend.
+func SeparateStemsWith(vr VocalRemover, path string) (vocals, accompaniment string, err error) {
+	key, err := cacheKey(path)
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", "", err
+	}
+
+	accompaniment = filepath.Join(cacheDir, key+".mp3")
+	vocals = filepath.Join(cacheDir, key+".vocals.mp3")
+	if _, err := os.Stat(accompaniment); err == nil {
+		return vocals, accompaniment, nil
+	}
+
+	if err := vr.Separate(path, vocals, accompaniment); err != nil {
+		return "", "", fmt.Errorf("separation: %w", err)
+	}
+	return vocals, accompaniment, nil
+}
+
+// defaultRemover picks the first installed backend, preferring Demucs for
+// quality, then Spleeter, then the ONNX UVR fallback. If none are on PATH,
+// Demucs is still returned so the caller gets a clear "not installed" error
+// instead of a silent no-op.
+func defaultRemover() VocalRemover {
+	for _, vr := range []VocalRemover{DemucsRemover{}, SpleeterRemover{}, ONNXUVRRemover{}} {
+		if isAvailable(vr) {
+			return vr
+		}
+	}
+	return DemucsRemover{}
+}
+
+func isAvailable(vr VocalRemover) bool {
+	type binaryNamer interface{ binary() string }
+	bn, ok := vr.(binaryNamer)
+	if !ok {
+		return false
+	}
+	_, err := exec.LookPath(bn.binary())
+	return err == nil
+}
+
+// cacheKey derives a stable cache key from the source file's path, size and
+// modification time, avoiding a full read of what may be a large audio file.
+func cacheKey(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s:%d:%d", abs, info.Size(), info.ModTime().UnixNano())))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// run executes a source-separation CLI, surfacing its stderr on failure.
+func run(cmd *exec.Cmd) error {
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Path, err, out)
+	}
+	return nil
+}
+
+// transcode re-encodes in (typically a separation tool's raw .wav stem) to
+// out via ffmpeg, so callers get back whatever container/codec they asked
+// for regardless of what the separation tool emits.
+func transcode(in, out string) error {
+	return run(exec.Command("ffmpeg", "-y", "-i", in, out))
+}
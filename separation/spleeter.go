@@ -0,0 +1,36 @@
+package separation
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SpleeterRemover separates stems via Deezer's Spleeter, a lighter-weight
+// alternative to Demucs.
+type SpleeterRemover struct{}
+
+func (SpleeterRemover) binary() string { return "spleeter" }
+
+func (SpleeterRemover) Separate(path, vocalsOut, accompanimentOut string) error {
+	tmp, err := os.MkdirTemp("", "lyricflow-spleeter-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := run(exec.Command("spleeter", "separate",
+		"-p", "spleeter:2stems", "-o", tmp, path)); err != nil {
+		return err
+	}
+
+	// Spleeter writes <out>/<source-stem>/{vocals,accompaniment}.wav.
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	outDir := filepath.Join(tmp, stem)
+
+	if err := transcode(filepath.Join(outDir, "vocals.wav"), vocalsOut); err != nil {
+		return err
+	}
+	return transcode(filepath.Join(outDir, "accompaniment.wav"), accompanimentOut)
+}
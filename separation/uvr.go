@@ -0,0 +1,35 @@
+package separation
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// uvrModelEnv points at a local ONNX UVR model file (e.g. a UVR-MDX-NET
+// checkpoint). We shell out rather than embed an ONNX Runtime binding,
+// matching how this package already treats Demucs/Spleeter as external
+// tools.
+const uvrModelEnv = "LYRICFLOW_UVR_MODEL"
+
+// ONNXUVRRemover is the last-resort backend: a thin wrapper around a
+// user-supplied "uvr-onnx" CLI front-end for an ONNX-runtime UVR model,
+// for environments where neither Demucs nor Spleeter (both Python/PyTorch)
+// are installable.
+type ONNXUVRRemover struct{}
+
+func (ONNXUVRRemover) binary() string { return "uvr-onnx" }
+
+func (ONNXUVRRemover) Separate(path, vocalsOut, accompanimentOut string) error {
+	model := os.Getenv(uvrModelEnv)
+	if model == "" {
+		return fmt.Errorf("separation: %s must point at an ONNX UVR model", uvrModelEnv)
+	}
+
+	return run(exec.Command("uvr-onnx",
+		"--model", model,
+		"--input", path,
+		"--vocals-out", vocalsOut,
+		"--instrumental-out", accompanimentOut,
+	))
+}
@@ -0,0 +1,169 @@
+// Package timing auto-corrects lyric timestamps against the actual vocal
+// track, for sources where the shipped LRC/lyric file drifts from the audio.
+package timing
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/DURUII/LyricFlow/lyrics"
+)
+
+// Options configures the VAD pass.
+type Options struct {
+	Tolerance          float64 // max seconds a boundary may be nudged, e.g. 0.8
+	NoiseFloor         string  // ffmpeg silencedetect noise threshold, e.g. "-30dB"
+	MinSilenceDuration float64 // silencedetect "d=" in seconds
+}
+
+// DefaultOptions returns the tolerances used when main doesn't override them.
+func DefaultOptions() Options {
+	return Options{
+		Tolerance:          0.8,
+		NoiseFloor:         "-30dB",
+		MinSilenceDuration: 0.2,
+	}
+}
+
+// Interval is a detected span of vocal activity, in seconds.
+type Interval struct {
+	Start float64
+	End   float64
+}
+
+// AlignToVAD runs voice-activity detection on audioPath, snaps each line's
+// Start to the nearest detected vocal onset within opts.Tolerance, and
+// propagates End from the next line's snapped Start so adjacent lines stay
+// contiguous. A snap is dropped (the original Start is kept) whenever it
+// would reorder lines: push a Start to or past its own original End, or to
+// or behind the Start of the line before it.
+func AlignToVAD(lines []lyrics.LyricLine, audioPath string, opts Options) ([]lyrics.LyricLine, error) {
+	intervals, err := detectVocalIntervals(audioPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return alignToIntervals(lines, intervals, opts), nil
+}
+
+// alignToIntervals is AlignToVAD's pure alignment core, split out from VAD
+// detection so the snap/propagate/drop-on-reorder logic can be tested
+// without shelling out to ffmpeg.
+func alignToIntervals(lines []lyrics.LyricLine, intervals []Interval, opts Options) []lyrics.LyricLine {
+	aligned := make([]lyrics.LyricLine, len(lines))
+	copy(aligned, lines)
+
+	// First pass: snap each Start, skipping any snap that would invert the
+	// line or reorder it against the (already finalized) previous line.
+	for i := range aligned {
+		snapped := snapToNearestOnset(lines[i].Start, intervals, opts.Tolerance)
+		if snapped >= lines[i].End {
+			continue
+		}
+		if i > 0 && snapped <= aligned[i-1].Start {
+			continue
+		}
+		aligned[i].Start = snapped
+	}
+
+	// Second pass: propagate End from the next line's finalized Start; the
+	// last line snaps its own End the same way, guarding against inversion.
+	for i := 0; i < len(aligned)-1; i++ {
+		if aligned[i+1].Start > aligned[i].Start {
+			aligned[i].End = aligned[i+1].Start
+		}
+	}
+	if n := len(aligned); n > 0 {
+		snapped := snapToNearestOnset(lines[n-1].End, intervals, opts.Tolerance)
+		if snapped > aligned[n-1].Start {
+			aligned[n-1].End = snapped
+		}
+	}
+
+	return aligned
+}
+
+// detectVocalIntervals shells out to ffmpeg's silencedetect filter and
+// inverts the reported silence spans into vocal-activity intervals.
+func detectVocalIntervals(audioPath string, opts Options) ([]Interval, error) {
+	cmd := exec.Command("ffmpeg", "-i", audioPath,
+		"-af", fmt.Sprintf("silencedetect=noise=%s:d=%.2f", opts.NoiseFloor, opts.MinSilenceDuration),
+		"-f", "null", "-")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// ffmpeg writes silencedetect output to stderr and exits 0 even with
+	// no output file, but tolerate a non-zero exit and still parse what it logged.
+	_ = cmd.Run()
+
+	return parseSilenceLog(stderr.Bytes())
+}
+
+var (
+	silenceStartRe = regexp.MustCompile(`silence_start:\s*(-?[\d.]+)`)
+	silenceEndRe   = regexp.MustCompile(`silence_end:\s*(-?[\d.]+)`)
+)
+
+// parseSilenceLog turns ffmpeg's silencedetect stderr log into the
+// complementary vocal intervals: the gaps between silences.
+func parseSilenceLog(log []byte) ([]Interval, error) {
+	var silences []Interval
+	var openStart float64
+	haveOpen := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := silenceStartRe.FindStringSubmatch(line); m != nil {
+			t, err := strconv.ParseFloat(m[1], 64)
+			if err != nil {
+				continue
+			}
+			openStart, haveOpen = t, true
+			continue
+		}
+		if m := silenceEndRe.FindStringSubmatch(line); m != nil {
+			t, err := strconv.ParseFloat(m[1], 64)
+			if err != nil || !haveOpen {
+				continue
+			}
+			silences = append(silences, Interval{Start: openStart, End: t})
+			haveOpen = false
+		}
+	}
+
+	return invertToVocalIntervals(silences), nil
+}
+
+// invertToVocalIntervals takes silence spans, assumed sorted by Start, and
+// returns the vocal activity between them.
+func invertToVocalIntervals(silences []Interval) []Interval {
+	var vocal []Interval
+	cursor := 0.0
+	for _, s := range silences {
+		if s.Start > cursor {
+			vocal = append(vocal, Interval{Start: cursor, End: s.Start})
+		}
+		cursor = s.End
+	}
+	return vocal
+}
+
+// snapToNearestOnset returns the start of the vocal interval whose onset is
+// closest to t, provided it's within tolerance; otherwise it returns t
+// unchanged.
+func snapToNearestOnset(t float64, intervals []Interval, tolerance float64) float64 {
+	best := t
+	bestDist := tolerance
+	for _, iv := range intervals {
+		if d := math.Abs(iv.Start - t); d <= bestDist {
+			bestDist = d
+			best = iv.Start
+		}
+	}
+	return best
+}
@@ -0,0 +1,121 @@
+package timing
+
+import (
+	"testing"
+
+	"github.com/DURUII/LyricFlow/lyrics"
+)
+
+func TestParseSilenceLog(t *testing.T) {
+	log := []byte(`[silencedetect @ 0x0] silence_start: 2.5
+[silencedetect @ 0x0] silence_end: 3.1 | silence_duration: 0.6
+[silencedetect @ 0x0] silence_start: 10
+[silencedetect @ 0x0] silence_end: 10.75 | silence_duration: 0.75
+`)
+
+	vocal, err := parseSilenceLog(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Interval{{Start: 0, End: 2.5}, {Start: 3.1, End: 10}}
+	if len(vocal) != len(want) {
+		t.Fatalf("got %v, want %v", vocal, want)
+	}
+	for i, iv := range vocal {
+		if iv != want[i] {
+			t.Errorf("interval %d = %+v, want %+v", i, iv, want[i])
+		}
+	}
+}
+
+func TestInvertToVocalIntervals(t *testing.T) {
+	silences := []Interval{{Start: 1, End: 2}, {Start: 2, End: 3}, {Start: 5, End: 6}}
+	got := invertToVocalIntervals(silences)
+	want := []Interval{{Start: 0, End: 1}, {Start: 3, End: 5}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, iv := range got {
+		if iv != want[i] {
+			t.Errorf("interval %d = %+v, want %+v", i, iv, want[i])
+		}
+	}
+}
+
+func TestSnapToNearestOnsetWithinTolerance(t *testing.T) {
+	intervals := []Interval{{Start: 1.0, End: 4.0}, {Start: 5.0, End: 8.0}}
+	if got := snapToNearestOnset(1.3, intervals, 0.5); got != 1.0 {
+		t.Errorf("snapToNearestOnset = %v, want 1.0", got)
+	}
+}
+
+func TestSnapToNearestOnsetOutsideTolerance(t *testing.T) {
+	intervals := []Interval{{Start: 1.0, End: 4.0}}
+	if got := snapToNearestOnset(3.0, intervals, 0.5); got != 3.0 {
+		t.Errorf("snapToNearestOnset = %v, want unchanged 3.0", got)
+	}
+}
+
+func TestSnapToNearestOnsetPicksClosest(t *testing.T) {
+	intervals := []Interval{{Start: 1.0, End: 2.0}, {Start: 1.3, End: 2.0}}
+	if got := snapToNearestOnset(1.2, intervals, 1.0); got != 1.3 {
+		t.Errorf("snapToNearestOnset = %v, want the closer onset 1.3", got)
+	}
+}
+
+func TestAlignToIntervalsPropagatesEndFromNextStart(t *testing.T) {
+	lines := []lyrics.LyricLine{
+		{Start: 1.0, End: 4.0, Text: "first"},
+		{Start: 4.1, End: 8.0, Text: "second"},
+	}
+	intervals := []Interval{{Start: 1.2, End: 3.9}, {Start: 4.3, End: 7.8}}
+	opts := Options{Tolerance: 0.5}
+
+	aligned := alignToIntervals(lines, intervals, opts)
+
+	if aligned[0].Start != 1.2 {
+		t.Errorf("line 0 Start = %v, want snapped onset 1.2", aligned[0].Start)
+	}
+	if aligned[0].End != aligned[1].Start {
+		t.Errorf("line 0 End = %v, want it to equal line 1's snapped Start %v", aligned[0].End, aligned[1].Start)
+	}
+	if aligned[1].Start != 4.3 {
+		t.Errorf("line 1 Start = %v, want snapped onset 4.3", aligned[1].Start)
+	}
+}
+
+func TestAlignToIntervalsDropsSnapThatWouldInvertLine(t *testing.T) {
+	// The nearest onset (1.3) falls past this short line's own End (1.2), so
+	// the snap must be dropped.
+	lines := []lyrics.LyricLine{
+		{Start: 1.0, End: 1.2, Text: "ad-lib"},
+	}
+	intervals := []Interval{{Start: 1.3, End: 2.0}}
+	opts := Options{Tolerance: 0.5}
+
+	aligned := alignToIntervals(lines, intervals, opts)
+
+	if aligned[0].Start != 1.0 {
+		t.Errorf("Start = %v, want the original 1.0 (snap to 1.3 would invert Start>=End)", aligned[0].Start)
+	}
+}
+
+func TestAlignToIntervalsDropsSnapThatWouldReorderLines(t *testing.T) {
+	lines := []lyrics.LyricLine{
+		{Start: 1.0, End: 5.0, Text: "first"},
+		{Start: 2.0, End: 6.0, Text: "second"},
+	}
+	// Both lines are nearest to the same onset at 1.9 — snapping line 1's
+	// Start there would put it level with line 0's (already finalized) Start.
+	intervals := []Interval{{Start: 1.9, End: 6.0}}
+	opts := Options{Tolerance: 1.5}
+
+	aligned := alignToIntervals(lines, intervals, opts)
+
+	if aligned[0].Start != 1.9 {
+		t.Errorf("line 0 Start = %v, want snapped onset 1.9", aligned[0].Start)
+	}
+	if aligned[1].Start != 2.0 {
+		t.Errorf("line 1 Start = %v, want the original 2.0 (snap to 1.9 would reorder against line 0)", aligned[1].Start)
+	}
+}